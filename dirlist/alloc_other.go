@@ -0,0 +1,12 @@
+//go:build !unix
+
+package dirlist
+
+import (
+	"os"
+)
+
+// getAllocatedSize doesn't know the space used on disk outside Unix
+func getAllocatedSize(info os.FileInfo) (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,18 @@
+//go:build unix
+
+package dirlist
+
+import (
+	"os"
+	"syscall"
+)
+
+// getAllocatedSize returns the space used on disk, st_blocks always being
+// in 512 bytes units whatever the block size of the file system
+func getAllocatedSize(info os.FileInfo) (int64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int64(stat.Blocks) * 512, true
+}
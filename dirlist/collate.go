@@ -0,0 +1,154 @@
+package dirlist
+
+import (
+	"os"
+	"strings"
+	"unicode"
+)
+
+// LocaleFromEnvironment returns the collation locale the same way as the C
+// library does: LC_ALL, then LC_COLLATE, then LANG
+func LocaleFromEnvironment() string {
+	for _, x := range []string{"LC_ALL", "LC_COLLATE", "LANG"} {
+		if locale := os.Getenv(x); locale != "" {
+			return locale
+		}
+	}
+	return ""
+}
+
+// localeLanguage returns the language part of a locale, e.g. "de" for
+// "de_DE.UTF-8", or "" for the C and POSIX locales
+func localeLanguage(locale string) string {
+	language := strings.ToLower(locale)
+	if i := strings.IndexAny(language, "_-.@"); i >= 0 {
+		language = language[:i]
+	}
+	if language == "c" || language == "posix" {
+		return ""
+	}
+	return language
+}
+
+// baseLetters removes the accents of the (already case folded) Latin
+// letters, which is how they compare in most languages
+var baseLetters = map[rune]string{}
+
+func init() {
+	for base, letters := range map[string]string{
+		"a": "àáâãäåāăą", "c": "çćĉċč", "d": "ďđð", "e": "èéêëēĕėęě",
+		"g": "ĝğġģ", "h": "ĥħ", "i": "ìíîïĩīĭįı", "j": "ĵ", "k": "ķ",
+		"l": "ĺļľŀł", "n": "ñńņň", "o": "òóôõöøōŏő", "r": "ŕŗř",
+		"s": "śŝşš", "t": "ţťŧ", "u": "ùúûüũūŭůűų", "w": "ŵ", "y": "ýÿŷ",
+		"z": "źżž", "ae": "æ", "oe": "œ", "ij": "ĳ", "th": "þ",
+	} {
+		for _, x := range letters {
+			baseLetters[x] = base
+		}
+	}
+}
+
+// Letters that some languages sort as separate letters of the alphabet.
+// Appending U+FFFF to the preceding letter sorts them after all the
+// words using that letter.
+const afterLetter = "\uffff"
+
+var tailoredLetters = map[string]map[rune]string{
+	"tr": {'ç': "c" + afterLetter, 'ğ': "g" + afterLetter,
+		'ı': "h" + afterLetter, 'ö': "o" + afterLetter,
+		'ş': "s" + afterLetter, 'ü': "u" + afterLetter},
+	"sv": {'å': "z" + afterLetter, 'ä': "z" + afterLetter + afterLetter,
+		'ö': "z" + afterLetter + afterLetter + afterLetter},
+	"da": {'æ': "z" + afterLetter, 'ø': "z" + afterLetter + afterLetter,
+		'å': "z" + afterLetter + afterLetter + afterLetter},
+	"es": {'ñ': "n" + afterLetter},
+}
+
+func init() {
+	tailoredLetters["az"] = tailoredLetters["tr"]
+	tailoredLetters["fi"] = tailoredLetters["sv"]
+	tailoredLetters["nb"] = tailoredLetters["da"]
+	tailoredLetters["nn"] = tailoredLetters["da"]
+	tailoredLetters["no"] = tailoredLetters["da"]
+}
+
+// collator compares names according to the rules of a locale. This is not
+// a full implementation of the Unicode collation algorithm but it handles
+// accents, case and the letters that are sorted differently in the common
+// European languages.
+type collator struct {
+	isByteOrder bool // C or POSIX locale: compare the lower case bytes
+	isTurkish   bool // dotted and dotless i are different letters
+	tailored    map[rune]string
+}
+
+func newCollator(locale string) collator {
+	language := localeLanguage(locale)
+	return collator{isByteOrder: language == "",
+		isTurkish: language == "tr" || language == "az",
+		tailored:  tailoredLetters[language]}
+}
+
+// FoldCase returns s with the case differences removed using Unicode case
+// folding (e.g. "Straße" and "STRASSE" both give "strasse") and the Turkish
+// dotted and dotless I for the tr and az locales
+func FoldCase(s, locale string) string {
+	return newCollator(locale).fold(s)
+}
+
+func (c collator) fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, x := range s {
+		switch {
+		case x == 'ß' || x == 'ẞ':
+			b.WriteString("ss")
+		case c.isTurkish:
+			b.WriteRune(unicode.TurkishCase.ToLower(
+				unicode.TurkishCase.ToUpper(x)))
+		case x == 'İ':
+			b.WriteRune('i')
+		default:
+			// ToLower(ToUpper()) also folds the letters like 'ſ' and 'ς'
+			// that have more than one lower case form
+			b.WriteRune(unicode.ToLower(unicode.ToUpper(x)))
+		}
+	}
+	return b.String()
+}
+
+// primaryKey returns the case folded s with the accents removed (except for
+// the letters tailored by the locale)
+func (c collator) primaryKey(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, x := range c.fold(s) {
+		if letter, ok := c.tailored[x]; ok {
+			b.WriteString(letter)
+		} else if base, ok := baseLetters[x]; ok {
+			b.WriteString(base)
+		} else {
+			b.WriteRune(x)
+		}
+	}
+	return b.String()
+}
+
+// compare compares two names first ignoring accents and case, then
+// ignoring case only and finally putting lower case before upper case
+func (c collator) compare(a, b string, natural bool) int {
+	compare := strings.Compare
+	if natural {
+		compare = naturalCompare
+	}
+	if c.isByteOrder {
+		return compare(strings.ToLower(a), strings.ToLower(b))
+	}
+	if r := compare(c.primaryKey(a), c.primaryKey(b)); r != 0 {
+		return r
+	}
+	if r := compare(c.fold(a), c.fold(b)); r != 0 {
+		return r
+	}
+	return -strings.Compare(a, b)
+}
@@ -0,0 +1,57 @@
+package dirlist
+
+import (
+	"testing"
+)
+
+func TestCollatorCompare(t *testing.T) {
+	tests := []struct {
+		locale, a, b string
+		want         int
+	}{
+		{"C", "B", "a", 1},
+		{"C", "é", "f", 1}, // byte order
+		{"en_US.UTF-8", "é", "f", -1},
+		{"en_US.UTF-8", "résumé", "resume", 1},
+		{"en_US.UTF-8", "resume", "Resume", -1},
+		{"en_US.UTF-8", "Zebra", "apple", 1},
+		{"de_DE.UTF-8", "Straße", "strasse", 1},
+		{"de_DE.UTF-8", "Straße", "strassf", -1},
+		{"sv_SE.UTF-8", "ö", "z", 1},
+		{"sv_SE.UTF-8", "å", "ä", -1},
+		{"en_US.UTF-8", "ö", "z", -1},
+		{"es_ES.UTF-8", "ñ", "o", -1},
+		{"es_ES.UTF-8", "ñ", "nz", 1},
+		{"tr_TR.UTF-8", "ı", "i", -1},
+	}
+	for _, x := range tests {
+		got := newCollator(x.locale).compare(x.a, x.b, false)
+		if got != x.want {
+			t.Errorf("compare(%q, %q) in %s = %d, want %d", x.a, x.b,
+				x.locale, got, x.want)
+		}
+	}
+	c := newCollator("en_US.UTF-8")
+	if got := c.compare("file2", "File10", true); got != -1 {
+		t.Errorf("natural compare(\"file2\", \"File10\") = %d, want -1", got)
+	}
+}
+
+func TestFoldCase(t *testing.T) {
+	tests := []struct {
+		s, locale, want string
+	}{
+		{"README.TXT", "", "readme.txt"},
+		{"Straße", "de_DE", "strasse"},
+		{"STRASSE", "de_DE", "strasse"},
+		{"ΣΊΣΥΦΟΣ", "el_GR", "σίσυφοσ"},
+		{"İstanbul", "en_US", "istanbul"},
+		{"DİYARBAKIR", "tr_TR", "diyarbakır"},
+	}
+	for _, x := range tests {
+		if got := FoldCase(x.s, x.locale); got != x.want {
+			t.Errorf("FoldCase(%q, %q) = %q, want %q", x.s, x.locale, got,
+				x.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package dirlist
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CSVColumns are the columns that can be selected for /csv and /tsv
+var CSVColumns = []string{"name", "relpath", "fullpath", "size",
+	"allocated", "links", "mtime", "mode", "dir", "target", "hidden",
+	"system", "readonly"}
+
+// DefaultCSVColumns are used when no column is selected
+var DefaultCSVColumns = []string{"name", "relpath", "size", "mtime", "mode",
+	"dir"}
+
+// ParseColumns parses a comma separated list of CSVColumns
+func ParseColumns(spec string) ([]string, error) {
+	columns := []string{}
+	for _, x := range strings.Split(spec, ",") {
+		x = strings.ToLower(strings.TrimSpace(x))
+		found := false
+		for _, y := range CSVColumns {
+			if x == y {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("Unknown column \"%s\", must be one of %s",
+				x, strings.Join(CSVColumns, ","))
+		}
+		columns = append(columns, x)
+	}
+	return columns, nil
+}
+
+func (l *Lister) csvColumns() []string {
+	if len(l.Columns) == 0 {
+		return DefaultCSVColumns
+	}
+	return l.Columns
+}
+
+// newCSVWriter creates the csv.Writer (quoting per RFC 4180) and writes the
+// header row
+func (l *Lister) newCSVWriter() error {
+	l.csvWriter = csv.NewWriter(l.Out)
+	if l.IsTSVFormat {
+		l.csvWriter.Comma = '\t'
+	}
+	return l.csvWriter.Write(l.csvColumns())
+}
+
+func (l *Lister) writeCSVRecord(entry Record) error {
+	if l.csvWriter == nil {
+		if err := l.newCSVWriter(); err != nil {
+			return err
+		}
+	}
+	columns := l.csvColumns()
+	record := make([]string, len(columns))
+	for i, x := range columns {
+		switch x {
+		case "name":
+			record[i] = entry.Name
+		case "relpath":
+			record[i] = l.relativePathName(entry.Path)
+		case "fullpath":
+			record[i] = entry.Path
+		case "size":
+			record[i] = strconv.FormatInt(entry.Size, 10)
+		case "allocated":
+			record[i] = strconv.FormatInt(entry.AllocatedSize, 10)
+		case "links":
+			record[i] = strconv.FormatUint(entry.Links, 10)
+		case "mtime":
+			record[i] = entry.ModTime.Format(time.RFC3339)
+		case "mode":
+			record[i] = entry.Mode
+		case "dir":
+			record[i] = strconv.FormatBool(entry.IsDir)
+		case "target":
+			record[i] = entry.SymlinkTarget
+		case "hidden":
+			record[i] = strconv.FormatBool(entry.IsHidden)
+		case "system":
+			record[i] = strconv.FormatBool(entry.IsSystem)
+		case "readonly":
+			record[i] = strconv.FormatBool(entry.IsReadOnly)
+		}
+	}
+	return l.csvWriter.Write(record)
+}
+
+// flushCSV writes the header if nothing was listed and flushes the output
+func (l *Lister) flushCSV() error {
+	if l.csvWriter == nil {
+		if err := l.newCSVWriter(); err != nil {
+			return err
+		}
+	}
+	l.csvWriter.Flush()
+	return l.csvWriter.Error()
+}
@@ -0,0 +1,86 @@
+package dirlist
+
+import (
+	"path/filepath"
+
+	"github.com/tsaost/util"
+)
+
+// directorySize returns the total of the files below the subdirectory
+// pathName of parent, whose walkState is w, with the same filters as the
+// listing except the depth and the directory only ones, like du does. Each
+// file with several links is counted once, the directories that can't be
+// read are skipped and the warnings are added to parent.
+//
+// The totals of the directories below pathName that the listing goes on to
+// list are kept in directorySizes, so that each directory is only read
+// once for the sizes however deep the listing goes.
+func (l *Lister) directorySize(parent *Directory, pathName string,
+	patterns []string, w walkState) Summary {
+	if x, ok := l.directorySizes.LoadAndDelete(pathName); ok {
+		return x.(Summary)
+	}
+	// pathName is listed next if the listing descends into it
+	isListed := len(parent.SubDirectories) > 0 &&
+		parent.SubDirectories[len(parent.SubDirectories)-1] == pathName
+	w.isSizing = true
+	child, err := l.childWalkState(w, pathName)
+	if err != nil {
+		return Summary{}
+	}
+	total, _ := l.subtreeSize(parent, pathName, patterns, child, isListed)
+	return total
+}
+
+// subtreeSize returns the total of the files below directory and the files
+// with several links among them, so that the directory above counts them
+// once too. If isListed, the totals of its subdirectories are kept in
+// directorySizes for when their entries are listed.
+func (l *Lister) subtreeSize(parent *Directory, directory string,
+	patterns []string, w walkState,
+	isListed bool) (Summary, map[fileID]hardLink) {
+	d := &Directory{Path: directory}
+	err := l.readEntries(d, patterns, w, func([]util.PathInfo) error {
+		return nil
+	})
+	parent.Warnings = append(parent.Warnings, d.Warnings...)
+	if err != nil {
+		return Summary{}, nil
+	}
+	total := d.Summary
+	links := map[fileID]hardLink{}
+	addLink := func(x hardLink) {
+		if _, ok := links[x.id]; ok {
+			total.FilesSize -= x.size
+			total.AllocatedSize -= x.allocated
+		} else {
+			links[x.id] = x
+		}
+	}
+	for _, x := range d.hardLinks {
+		addLink(x)
+	}
+	listing := w
+	listing.isSizing = false
+	for _, x := range d.SubDirectories {
+		child, err := l.childWalkState(w, x)
+		if err != nil {
+			continue
+		}
+		isChildListed := isListed && l.isDescendable(listing,
+			filepath.Base(x))
+		s, childLinks := l.subtreeSize(parent, x, patterns, child,
+			isChildListed)
+		total.FilesCount += s.FilesCount
+		total.DirectoriesCount += s.DirectoriesCount
+		total.FilesSize += s.FilesSize
+		total.AllocatedSize += s.AllocatedSize
+		for _, y := range childLinks {
+			addLink(y)
+		}
+		if isListed {
+			l.directorySizes.Store(x, s)
+		}
+	}
+	return total, links
+}
@@ -0,0 +1,37 @@
+//go:build !unix
+
+package dirlist
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// fileID identifies a file independently of the path used to reach it.
+// Without inode numbers the path with all the links resolved is used.
+type fileID struct {
+	path string
+}
+
+// getFileID returns the path of pathName with all the links resolved
+func getFileID(pathName string, info os.FileInfo) (fileID, bool) {
+	path, err := filepath.EvalSymlinks(pathName)
+	if err != nil {
+		return fileID{}, false
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return fileID{}, false
+	}
+	return fileID{path: path}, true
+}
+
+// getDeviceID is not available, mount points are only found from the list
+// of mounted file systems
+func getDeviceID(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
+
+// getLinkCount is not available, every file is taken as having one link
+func getLinkCount(info os.FileInfo) (uint64, bool) {
+	return 0, false
+}
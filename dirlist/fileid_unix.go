@@ -0,0 +1,40 @@
+//go:build unix
+
+package dirlist
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileID identifies a file independently of the path used to reach it
+type fileID struct {
+	device, inode uint64
+}
+
+// getFileID returns the device and inode of info
+func getFileID(pathName string, info os.FileInfo) (fileID, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileID{}, false
+	}
+	return fileID{device: uint64(stat.Dev), inode: uint64(stat.Ino)}, true
+}
+
+// getDeviceID returns the device of the file system info is on
+func getDeviceID(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Dev), true
+}
+
+// getLinkCount returns the number of hard links to the file of info
+func getLinkCount(info os.FileInfo) (uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return uint64(stat.Nlink), true
+}
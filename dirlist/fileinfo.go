@@ -0,0 +1,141 @@
+package dirlist
+
+import (
+	"os"
+	"time"
+
+	"github.com/tsaost/util"
+)
+
+// lazyInfo is the os.FileInfo of an os.DirEntry: the name and the type come
+// from the directory itself and the Lstat() is only done the first time
+// something else is needed, so the entries that are filtered out by name
+// are never stat-ed
+type lazyInfo struct {
+	entry  os.DirEntry
+	info   os.FileInfo
+	isRead bool
+}
+
+// The zero values are returned if the file is gone by the time it is stat-ed
+func (x *lazyInfo) load() os.FileInfo {
+	if !x.isRead {
+		x.isRead = true
+		x.info, _ = x.entry.Info()
+	}
+	return x.info
+}
+
+func (x *lazyInfo) Name() string { return x.entry.Name() }
+func (x *lazyInfo) IsDir() bool  { return x.entry.IsDir() }
+
+func (x *lazyInfo) Size() int64 {
+	if info := x.load(); info != nil {
+		return info.Size()
+	}
+	return 0
+}
+
+func (x *lazyInfo) Mode() os.FileMode {
+	if info := x.load(); info != nil {
+		return info.Mode()
+	}
+	return x.entry.Type()
+}
+
+func (x *lazyInfo) ModTime() time.Time {
+	if info := x.load(); info != nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+func (x *lazyInfo) Sys() interface{} {
+	if info := x.load(); info != nil {
+		return info.Sys()
+	}
+	return nil
+}
+
+// extendedInfo is an entry with what is worked out once when it is read
+// rather than each time it is sorted or displayed: its TimeField time and,
+// with IsShowDirectorySize, the total of what is below a directory, which
+// is then its Size()
+type extendedInfo struct {
+	util.PathInfo
+	time      time.Time // zero if TimeField is not set
+	directory *Summary
+}
+
+func (x *extendedInfo) Size() int64 {
+	if x.directory != nil {
+		return x.directory.FilesSize
+	}
+	return x.PathInfo.Size()
+}
+
+// entryTime returns the time that is displayed and sorted on, the
+// modification time unless TimeField is set
+func entryTime(info util.PathInfo) time.Time {
+	switch x := info.(type) {
+	case *extendedInfo:
+		if !x.time.IsZero() {
+			return x.time
+		}
+	case *compactInfo:
+		return x.time
+	}
+	return info.ModTime()
+}
+
+// directorySummary returns the total of what is below the directory info
+// with IsShowDirectorySize, nil otherwise
+func directorySummary(info util.PathInfo) *Summary {
+	switch x := info.(type) {
+	case *extendedInfo:
+		return x.directory
+	case *compactInfo:
+		return x.directory
+	}
+	return nil
+}
+
+// compactInfo keeps only what the Windows, wide and bare listings and the
+// sort keys use, which is much smaller than the Sys() of a full
+// os.FileInfo when the entries of huge directories have to be kept to be
+// sorted. The device is kept for isMountPoint().
+type compactInfo struct {
+	name, pathName  string
+	size, allocated int64
+	links           uint64
+	device          uint64
+	hasDevice       bool
+	mode            os.FileMode
+	modTime, time   time.Time // time is the entryTime()
+	directory       *Summary
+}
+
+func (x *compactInfo) Name() string       { return x.name }
+func (x *compactInfo) PathName() string   { return x.pathName }
+func (x *compactInfo) IsDir() bool        { return x.mode.IsDir() }
+func (x *compactInfo) Size() int64        { return x.size }
+func (x *compactInfo) Mode() os.FileMode  { return x.mode }
+func (x *compactInfo) ModTime() time.Time { return x.modTime }
+func (x *compactInfo) Sys() interface{}   { return nil }
+
+// retainedInfo returns what must be kept of info until the entries are
+// sorted and printed
+func (l *Lister) retainedInfo(info util.PathInfo) util.PathInfo {
+	if l.IsUnixStyleListing {
+		// ls -l needs the owner and the link count from Sys()
+		return info
+	}
+	x := &compactInfo{name: info.Name(), pathName: info.PathName(),
+		size: info.Size(), allocated: allocatedSize(info),
+		links: linkCount(info), mode: info.Mode(), modTime: info.ModTime(),
+		time: entryTime(info), directory: directorySummary(info)}
+	if x.IsDir() {
+		x.device, x.hasDevice = getDeviceID(info)
+	}
+	return x
+}
@@ -0,0 +1,131 @@
+package dirlist
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// The time fields of a file that can be shown, sorted on and filtered on
+const (
+	WriteTime  = 'w' // last modification
+	AccessTime = 'a' // last access
+	ChangeTime = 's' // last status change (Unix)
+	BirthTime  = 'b' // creation
+)
+
+// ParseTimeField parses the letter of a time field, the same for /t: and
+// /df:. Like dir /t:c, c is the creation time so it is the same as b, the
+// Unix status change time being s.
+func ParseTimeField(spec string) (byte, error) {
+	if spec == "c" {
+		return BirthTime, nil
+	}
+	if len(spec) == 1 && strings.IndexByte("wasb", spec[0]) >= 0 {
+		return spec[0], nil
+	}
+	return 0, fmt.Errorf("Bad time field \"%s\", must be one of "+
+		"w(rite), a(ccess), c(reation), b(irth) or s(tatus change)", spec)
+}
+
+var timeFieldNames = map[byte]string{
+	AccessTime: "access", ChangeTime: "change", BirthTime: "birth",
+}
+
+// getTime returns the field time of info, or the modification time if the
+// platform or the file system doesn't have that field
+func getTime(pathName string, info os.FileInfo, field byte) time.Time {
+	if field != 0 && field != WriteTime {
+		if t, ok := getFileTime(pathName, info, field); ok {
+			return t
+		}
+	}
+	return info.ModTime()
+}
+
+// FileTime returns the field time of the file pathName, following links
+func FileTime(pathName string, field byte) (time.Time, error) {
+	info, err := os.Stat(pathName)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return getTime(pathName, info, field), nil
+}
+
+// durationUnits are the suffixes of the durations, Go's time.ParseDuration
+// has no days or weeks
+var durationUnits = map[byte]time.Duration{
+	's': time.Second, 'm': time.Minute, 'h': time.Hour,
+	'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour,
+}
+
+// dateLayouts are the ISO 8601 forms accepted for dates, in local time
+// unless a time zone is given
+var dateLayouts = []string{
+	time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseTimeSpec parses the time of /since: or /until:, either an ISO date
+// like 2024-01-31 or 2024-01-31T13:45 or a duration like 90m, 6h, 3d or 2w
+// before now. isDateOnly tells that it is a whole day.
+func ParseTimeSpec(spec string, now time.Time) (t time.Time,
+	isDateOnly bool, err error) {
+	if n := len(spec); n > 1 {
+		if unit, ok := durationUnits[spec[n-1]]; ok {
+			if value, err := strconv.Atoi(spec[:n-1]); err == nil &&
+				value >= 0 {
+				return now.Add(-time.Duration(value) * unit), false, nil
+			}
+		}
+	}
+	for _, x := range dateLayouts {
+		if t, err = time.ParseInLocation(x, spec, time.Local); err == nil {
+			return t, x == "2006-01-02", nil
+		}
+	}
+	return t, false, fmt.Errorf("Bad date \"%s\", must be like 2024-01-31, "+
+		"2024-01-31T13:45 or a duration like 90m, 6h, 3d or 2w", spec)
+}
+
+// selectedTime returns the TimeField time of info, or its modification
+// time if it doesn't have one, which warnTimeFallback() then reports
+func (l *Lister) selectedTime(pathName string, info os.FileInfo) time.Time {
+	if t, ok := getFileTime(pathName, info, l.TimeField); ok {
+		return t
+	}
+	atomic.StoreInt32(&l.timeFallback, 1)
+	return info.ModTime()
+}
+
+// warnTimeFallback prints once that the write time was shown for the
+// entries that have no TimeField time. It is only called when a listing is
+// done, so the warning is in the same place whichever goroutine found them.
+func (l *Lister) warnTimeFallback() {
+	if l.isTimeFallbackWarned || atomic.LoadInt32(&l.timeFallback) == 0 {
+		return
+	}
+	l.isTimeFallbackWarned = true
+	fmt.Fprintf(l.messageOut(), "Warning: some entries have no %s time, the "+
+		"write time is shown instead\n", timeFieldNames[l.TimeField])
+}
+
+// isTimeSelected tells whether the entry is between FileCutoffTime and
+// FileUntilTime for DateFilterField, or TimeField if it is not set
+func (l *Lister) isTimeSelected(pathName string, info os.FileInfo) bool {
+	if l.FileCutoffTime.IsZero() && l.FileUntilTime.IsZero() {
+		return true
+	}
+	field := l.DateFilterField
+	if field == 0 {
+		field = l.TimeField
+	}
+	t := getTime(pathName, info, field)
+	if t.Before(l.FileCutoffTime) {
+		return false
+	}
+	return l.FileUntilTime.IsZero() || t.Before(l.FileUntilTime)
+}
@@ -0,0 +1,26 @@
+//go:build darwin || freebsd || netbsd
+
+package dirlist
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+func getFileTime(pathName string, info os.FileInfo,
+	field byte) (time.Time, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch field {
+	case AccessTime:
+		return time.Unix(stat.Atimespec.Unix()), true
+	case ChangeTime:
+		return time.Unix(stat.Ctimespec.Unix()), true
+	case BirthTime:
+		return time.Unix(stat.Birthtimespec.Unix()), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,95 @@
+package dirlist
+
+import (
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// statxSyscalls are the numbers of the statx system call, which the
+// syscall package doesn't have, on the architectures where it is known
+var statxSyscalls = map[string]uintptr{
+	"amd64": 332, "386": 383, "arm": 397, "arm64": 291, "riscv64": 291,
+	"loong64": 291, "ppc64": 383, "ppc64le": 383, "s390x": 379,
+}
+
+const (
+	atFileDescriptorCWD = -100  // AT_FDCWD
+	atSymlinkNoFollow   = 0x100 // AT_SYMLINK_NOFOLLOW
+	statxBirthTime      = 0x800 // STATX_BTIME
+)
+
+type statxTimestamp struct {
+	Sec  int64
+	Nsec uint32
+	_    int32
+}
+
+// statxResult is the struct statx of <linux/stat.h>, 256 bytes
+type statxResult struct {
+	Mask           uint32
+	Blksize        uint32
+	Attributes     uint64
+	Nlink          uint32
+	Uid            uint32
+	Gid            uint32
+	Mode           uint16
+	_              uint16
+	Ino            uint64
+	Size           uint64
+	Blocks         uint64
+	AttributesMask uint64
+	Atime          statxTimestamp
+	Btime          statxTimestamp
+	Ctime          statxTimestamp
+	Mtime          statxTimestamp
+	_              [128]byte
+}
+
+// getBirthTime uses statx() since stat() has no birth time, it is only
+// available since Linux 4.11 and on the file systems that record it. Like
+// info, the link itself is used if info is the one of a link and what it
+// points to otherwise.
+func getBirthTime(pathName string, info os.FileInfo) (time.Time, bool) {
+	number, ok := statxSyscalls[runtime.GOARCH]
+	if !ok {
+		return time.Time{}, false
+	}
+	path, err := syscall.BytePtrFromString(pathName)
+	if err != nil {
+		return time.Time{}, false
+	}
+	flags := 0
+	if info.Mode()&os.ModeSymlink != 0 {
+		flags = atSymlinkNoFollow
+	}
+	var result statxResult
+	fd := atFileDescriptorCWD
+	_, _, errno := syscall.Syscall6(number, uintptr(fd),
+		uintptr(unsafe.Pointer(path)), uintptr(flags), statxBirthTime,
+		uintptr(unsafe.Pointer(&result)), 0)
+	if errno != 0 || result.Mask&statxBirthTime == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(result.Btime.Sec, int64(result.Btime.Nsec)), true
+}
+
+func getFileTime(pathName string, info os.FileInfo,
+	field byte) (time.Time, bool) {
+	if field == BirthTime {
+		return getBirthTime(pathName, info)
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch field {
+	case AccessTime:
+		return time.Unix(stat.Atim.Unix()), true
+	case ChangeTime:
+		return time.Unix(stat.Ctim.Unix()), true
+	}
+	return time.Time{}, false
+}
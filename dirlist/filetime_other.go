@@ -0,0 +1,14 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !windows
+
+package dirlist
+
+import (
+	"os"
+	"time"
+)
+
+// Only the modification time is known here
+func getFileTime(pathName string, info os.FileInfo,
+	field byte) (time.Time, bool) {
+	return time.Time{}, false
+}
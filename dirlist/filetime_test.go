@@ -0,0 +1,62 @@
+package dirlist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeSpec(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		spec       string
+		want       time.Time
+		isDateOnly bool
+		ok         bool
+	}{
+		{"90m", now.Add(-90 * time.Minute), false, true},
+		{"6h", now.Add(-6 * time.Hour), false, true},
+		{"3d", now.Add(-3 * 24 * time.Hour), false, true},
+		{"2w", now.Add(-14 * 24 * time.Hour), false, true},
+		{"30s", now.Add(-30 * time.Second), false, true},
+		{"0d", now, false, true},
+		{"2024-01-31", time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local),
+			true, true},
+		{"2024-01-31T13:45", time.Date(2024, 1, 31, 13, 45, 0, 0,
+			time.Local), false, true},
+		{"2024-01-31T13:45:07", time.Date(2024, 1, 31, 13, 45, 7, 0,
+			time.Local), false, true},
+		{"2024-01-31T13:45:07Z", time.Date(2024, 1, 31, 13, 45, 7, 0,
+			time.UTC), false, true},
+		{"", time.Time{}, false, false},
+		{"d", time.Time{}, false, false},
+		{"-3d", time.Time{}, false, false},
+		{"3y", time.Time{}, false, false},
+		{"2024-13-01", time.Time{}, false, false},
+		{"31/01/2024", time.Time{}, false, false},
+	}
+	for _, x := range tests {
+		got, isDateOnly, err := ParseTimeSpec(x.spec, now)
+		if (err == nil) != x.ok || (x.ok && (!got.Equal(x.want) ||
+			isDateOnly != x.isDateOnly)) {
+			t.Errorf("ParseTimeSpec(%q) = %v, %v, %v, want %v, %v", x.spec,
+				got, isDateOnly, err, x.want, x.isDateOnly)
+		}
+	}
+}
+
+func TestParseTimeField(t *testing.T) {
+	tests := []struct {
+		spec string
+		want byte
+	}{
+		{"w", WriteTime}, {"a", AccessTime}, {"s", ChangeTime},
+		{"b", BirthTime}, {"c", BirthTime}, {"x", 0}, {"", 0}, {"wa", 0},
+	}
+	for _, x := range tests {
+		got, err := ParseTimeField(x.spec)
+		if got != x.want || (err == nil) != (x.want != 0) {
+			t.Errorf("ParseTimeField(%q) = %c, %v, want %c", x.spec, got,
+				err, x.want)
+		}
+	}
+}
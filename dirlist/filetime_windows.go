@@ -0,0 +1,23 @@
+package dirlist
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// Windows has no status change time
+func getFileTime(pathName string, info os.FileInfo,
+	field byte) (time.Time, bool) {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, false
+	}
+	switch field {
+	case AccessTime:
+		return time.Unix(0, data.LastAccessTime.Nanoseconds()), true
+	case BirthTime:
+		return time.Unix(0, data.CreationTime.Nanoseconds()), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,73 @@
+package dirlist
+
+import (
+	"os"
+)
+
+// hardLink is a file with more than one link, its size must only be added
+// once to the totals however many of its links are listed
+type hardLink struct {
+	id              fileID
+	size, allocated int64
+}
+
+// linkCount returns the number of hard links to info, 1 if not known
+func linkCount(info os.FileInfo) uint64 {
+	if x, ok := info.(*compactInfo); ok {
+		return x.links
+	}
+	if links, ok := getLinkCount(info); ok {
+		return links
+	}
+	return 1
+}
+
+// newHardLink returns the hardLink of the file pathName if it has more
+// than one link
+func newHardLink(pathName string, info os.FileInfo) (hardLink, bool) {
+	if linkCount(info) < 2 {
+		return hardLink{}, false
+	}
+	id, ok := getFileID(pathName, info)
+	if !ok {
+		return hardLink{}, false
+	}
+	return hardLink{id: id, size: info.Size(),
+		allocated: allocatedSize(info)}, true
+}
+
+// isCounted tells whether another link to the file of x has already been
+// added to the totals, and remembers x otherwise. It is only called in the
+// order of the output so the same link is always the one counted.
+func (l *Lister) isCounted(x hardLink) bool {
+	if l.countedLinks == nil {
+		l.countedLinks = map[fileID]bool{}
+	}
+	if l.countedLinks[x.id] {
+		return true
+	}
+	l.countedLinks[x.id] = true
+	return false
+}
+
+// countLinks takes out of the sizes of d those of its files with several
+// links that have already been counted, in d or before it, so that each
+// file is only added once to the directory sizes and to Totals
+func (l *Lister) countLinks(d *Directory) {
+	for _, x := range d.hardLinks {
+		if l.isCounted(x) {
+			d.FilesSize -= x.size
+			d.AllocatedSize -= x.allocated
+		}
+	}
+	d.hardLinks = nil
+}
+
+// addToTotals adds the counts of d to Totals, once countLinks() has been
+// called on d
+func (l *Lister) addToTotals(d *Directory) {
+	l.Totals.DirectoriesCount += d.DirectoriesCount
+	l.Totals.FilesCount += d.FilesCount
+	l.Totals.FilesSize += d.FilesSize
+	l.Totals.AllocatedSize += d.AllocatedSize
+}
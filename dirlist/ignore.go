@@ -0,0 +1,160 @@
+package dirlist
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is one pattern of a .gitignore file
+type ignoreRule struct {
+	pattern    PathPattern
+	isNegated  bool // !pattern re-includes what a previous rule ignored
+	isDirOnly  bool // pattern/ only matches directories
+	isAnchored bool // has a '/', so it is matched against the whole path
+}
+
+// ignoreList holds the rules of the ignore files of one directory, its
+// parent holds those of the directory above
+type ignoreList struct {
+	parent    *ignoreList
+	directory string
+	rules     []ignoreRule
+}
+
+// The ignore files that are read in each directory, the later ones take
+// precedence like in ripgrep
+var ignoreFileNames = []string{".gitignore", ".ignore"}
+
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	rule := ignoreRule{}
+	if !strings.HasSuffix(line, "\\ ") {
+		line = strings.TrimRight(line, " \t")
+	}
+	if line == "" || line[0] == '#' {
+		return rule, false
+	}
+	if line[0] == '!' {
+		rule.isNegated = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, "\\!") || strings.HasPrefix(line, "\\#") {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.isDirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false
+	}
+	rule.isAnchored = strings.Contains(line, "/")
+	rule.pattern = PathPattern(strings.Split(strings.TrimLeft(line, "/"), "/"))
+	return rule, true
+}
+
+// readIgnoreFile appends the rules of fileName to rules, a missing or
+// unreadable file simply has no rule
+func readIgnoreFile(fileName string, rules []ignoreRule) []ignoreRule {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return rules
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseIgnoreRule(scanner.Text()); ok {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// loadIgnoreFiles returns the ignoreList for directory on top of parent, or
+// parent itself if directory has no ignore file. The root of a nested
+// repository doesn't inherit the rules of parent, like git does.
+func loadIgnoreFiles(directory string, parent *ignoreList) *ignoreList {
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return parent
+	}
+	rules := []ignoreRule{}
+	if info, err := os.Stat(filepath.Join(directory, ".git")); err == nil {
+		// .git is a file in a submodule or a worktree
+		parent = nil
+		if info.IsDir() {
+			rules = readIgnoreFile(filepath.Join(directory, ".git", "info",
+				"exclude"), rules)
+		}
+	}
+	for _, x := range ignoreFileNames {
+		rules = readIgnoreFile(filepath.Join(directory, x), rules)
+	}
+	if len(rules) == 0 {
+		return parent
+	}
+	return &ignoreList{parent: parent, directory: directory, rules: rules}
+}
+
+// loadAncestorIgnoreFiles returns the ignoreList of the directories above
+// directory up to the root of the git repository it is in, if any. There is
+// none if directory is itself the root of a repository.
+func loadAncestorIgnoreFiles(directory string) *ignoreList {
+	directory, err := filepath.Abs(directory)
+	if err != nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(directory, ".git")); err == nil {
+		return nil
+	}
+	ancestors := []string{}
+	for x := filepath.Dir(directory); ; x = filepath.Dir(x) {
+		ancestors = append(ancestors, x)
+		if _, err := os.Stat(filepath.Join(x, ".git")); err == nil {
+			break
+		}
+		if x == filepath.Dir(x) {
+			// Not in a git repository
+			return nil
+		}
+	}
+	var ignores *ignoreList
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		ignores = loadIgnoreFiles(ancestors[i], ignores)
+	}
+	return ignores
+}
+
+func (r ignoreRule) match(path []string, isDir bool,
+	match func(pattern, name string) bool) bool {
+	if r.isDirOnly && !isDir {
+		return false
+	}
+	if !r.isAnchored {
+		return match(r.pattern[0], path[len(path)-1])
+	}
+	return r.pattern.Match(path, match)
+}
+
+// isIgnored tells whether pathName is ignored: the deepest ignore file
+// takes precedence and in each file the last matching rule wins
+func (x *ignoreList) isIgnored(pathName string, isDir bool,
+	match func(pattern, name string) bool) bool {
+	absolutePathName, err := filepath.Abs(pathName)
+	if err != nil {
+		return false
+	}
+	for ; x != nil; x = x.parent {
+		relativePath, err := filepath.Rel(x.directory, absolutePathName)
+		if err != nil {
+			continue
+		}
+		path := splitPath(relativePath)
+		for i := len(x.rules) - 1; i >= 0; i-- {
+			if x.rules[i].match(path, isDir, match) {
+				return !x.rules[i].isNegated
+			}
+		}
+	}
+	return false
+}
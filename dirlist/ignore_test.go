@@ -0,0 +1,141 @@
+package dirlist
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		line string
+		want ignoreRule
+		ok   bool
+	}{
+		{"", ignoreRule{}, false},
+		{"# comment", ignoreRule{}, false},
+		{"/", ignoreRule{}, false},
+		{"*.o", ignoreRule{pattern: PathPattern{"*.o"}}, true},
+		{"*.o  ", ignoreRule{pattern: PathPattern{"*.o"}}, true},
+		{"!keep.o", ignoreRule{pattern: PathPattern{"keep.o"},
+			isNegated: true}, true},
+		{"\\!bang", ignoreRule{pattern: PathPattern{"!bang"}}, true},
+		{"\\#hash", ignoreRule{pattern: PathPattern{"#hash"}}, true},
+		{"build/", ignoreRule{pattern: PathPattern{"build"},
+			isDirOnly: true}, true},
+		{"/build", ignoreRule{pattern: PathPattern{"build"},
+			isAnchored: true}, true},
+		{"doc/*.txt", ignoreRule{pattern: PathPattern{"doc", "*.txt"},
+			isAnchored: true}, true},
+		{"!/out/", ignoreRule{pattern: PathPattern{"out"}, isNegated: true,
+			isDirOnly: true, isAnchored: true}, true},
+		{"**/logs", ignoreRule{pattern: PathPattern{"**", "logs"},
+			isAnchored: true}, true},
+	}
+	for _, x := range tests {
+		got, ok := parseIgnoreRule(x.line)
+		if ok != x.ok || (ok && !reflect.DeepEqual(got, x.want)) {
+			t.Errorf("parseIgnoreRule(%q) = %+v, %v, want %+v, %v",
+				x.line, got, ok, x.want, x.ok)
+		}
+	}
+}
+
+func TestIgnoreListIsIgnored(t *testing.T) {
+	root := t.TempDir()
+	newList := func(parent *ignoreList, directory string,
+		lines ...string) *ignoreList {
+		x := &ignoreList{parent: parent, directory: directory}
+		for _, line := range lines {
+			if rule, ok := parseIgnoreRule(line); ok {
+				x.rules = append(x.rules, rule)
+			}
+		}
+		return x
+	}
+	top := newList(nil, root, "*.log", "!important.log", "build/",
+		"/vendor", "doc/*.txt")
+	sub := filepath.Join(root, "sub")
+	nested := newList(top, sub, "important.log")
+
+	match := func(pattern, name string) bool {
+		matched, err := filepath.Match(pattern, name)
+		return err == nil && matched
+	}
+	tests := []struct {
+		list  *ignoreList
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{top, "a.log", false, true},
+		{top, "a.txt", false, false},
+		{top, "important.log", false, false},       // negated
+		{top, "sub/deep/a.log", false, true},       // not anchored
+		{top, "build", true, true},                 // dir only
+		{top, "build", false, false},               // not a directory
+		{top, "sub/build", true, true},             // not anchored
+		{top, "vendor", true, true},                // anchored
+		{top, "sub/vendor", true, false},           // anchored elsewhere
+		{top, "doc/a.txt", false, true},            // anchored with a '/'
+		{top, "sub/doc/a.txt", false, false},       // only from the root
+		{nested, "sub/important.log", false, true}, // deeper file wins
+		{nested, "sub/other.log", false, true},     // from the parent
+	}
+	for _, x := range tests {
+		pathName := filepath.Join(root, filepath.FromSlash(x.path))
+		if got := x.list.isIgnored(pathName, x.isDir, match); got != x.want {
+			t.Errorf("isIgnored(%q, %v) = %v, want %v", x.path, x.isDir,
+				got, x.want)
+		}
+	}
+}
+
+func TestNestedRepositoryIgnoreFiles(t *testing.T) {
+	outer := t.TempDir()
+	inner := filepath.Join(outer, "inner")
+	for _, x := range []string{filepath.Join(outer, ".git"),
+		filepath.Join(inner, ".git"), filepath.Join(outer, "sub")} {
+		if err := os.MkdirAll(x, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range map[string]string{
+		filepath.Join(outer, ".gitignore"): "*.log\n",
+		filepath.Join(inner, ".gitignore"): "*.tmp\n",
+	} {
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	match := func(pattern, name string) bool {
+		matched, err := filepath.Match(pattern, name)
+		return err == nil && matched
+	}
+
+	if x := loadAncestorIgnoreFiles(inner); x != nil {
+		t.Errorf("the rules of the outer repository apply to %s", inner)
+	}
+	fromOuter := loadIgnoreFiles(outer, loadAncestorIgnoreFiles(outer))
+	fromInner := loadIgnoreFiles(inner, loadAncestorIgnoreFiles(inner))
+	tests := []struct {
+		list *ignoreList
+		path string
+		want bool
+	}{
+		{fromOuter, "a.log", true},
+		{loadIgnoreFiles(filepath.Join(outer, "sub"), fromOuter),
+			"sub/a.log", true},
+		{loadIgnoreFiles(inner, fromOuter), "inner/a.log", false},
+		{loadIgnoreFiles(inner, fromOuter), "inner/a.tmp", true},
+		{fromInner, "inner/a.log", false},
+		{fromInner, "inner/a.tmp", true},
+	}
+	for _, x := range tests {
+		pathName := filepath.Join(outer, filepath.FromSlash(x.path))
+		if got := x.list.isIgnored(pathName, false, match); got != x.want {
+			t.Errorf("isIgnored(%q) = %v, want %v", x.path, got, x.want)
+		}
+	}
+}
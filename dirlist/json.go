@@ -0,0 +1,33 @@
+package dirlist
+
+import (
+	"encoding/json"
+)
+
+// writeJSONEntry writes entry as an NDJSON line right away, or keeps it
+// until the end of the listing for /json
+func (l *Lister) writeJSONEntry(entry Record) error {
+	if l.IsNDJSONFormat {
+		return json.NewEncoder(l.Out).Encode(entry)
+	}
+	l.jsonEntries = append(l.jsonEntries, entry)
+	return nil
+}
+
+// writeJSONSummary ends a /json or /ndjson listing with the summary, after
+// all the entries for /json
+func (l *Lister) writeJSONSummary(summary SummaryRecord) error {
+	encoder := json.NewEncoder(l.Out)
+	if l.IsNDJSONFormat {
+		return encoder.Encode(summary)
+	}
+	entries := l.jsonEntries
+	if entries == nil {
+		entries = []Record{}
+	}
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Entries []Record      `json:"entries"`
+		Summary SummaryRecord `json:"summary"`
+	}{entries, summary})
+}
@@ -0,0 +1,814 @@
+/*
+Package dirlist is the listing engine behind xdir: it reads a directory,
+filters and sorts its entries according to an Options value, and renders them
+in the Windows dir, Unix ls -l, wide or bare format.
+
+All state lives in a Lister so several listings can run in the same process
+(or concurrently) without interfering with each other.
+*/
+package dirlist
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tsaost/util"
+	"github.com/tsaost/util/cmd"
+)
+
+// Summary holds the file and directory counts of a listing
+type Summary struct {
+	FilesCount, DirectoriesCount int
+	FilesSize                    int64
+	AllocatedSize                int64 // of the files, on disk
+}
+
+// Directory is the filtered and sorted content of one directory
+type Directory struct {
+	Path           string
+	LinkTarget     string // when Path is a link followed with IsFollowLinks
+	Infos          []util.PathInfo
+	SubDirectories []string // only filled when recursing into subdirectories
+	Summary
+	MaxSize    int64
+	MaxNameLen int
+
+	// Warnings are those found while reading, they are printed with the
+	// directory so they are in the same place whatever the Jobs
+	Warnings []string
+
+	hardLinks []hardLink // the files with more than one link
+}
+
+// warn adds a warning to those of d
+func (d *Directory) warn(format string, a ...interface{}) {
+	d.Warnings = append(d.Warnings, fmt.Sprintf(format, a...))
+}
+
+// Lister produces directory listings according to its Options and writes
+// them to Out. Totals accumulates the counts of everything listed so far.
+type Lister struct {
+	Options
+	Out    io.Writer
+	Totals Summary
+
+	jsonEntries []Record // kept until WriteSummary() for /json
+	csvWriter   *csv.Writer
+
+	mountPoints     map[string]bool
+	mountPointsOnce sync.Once
+
+	flat *Directory // the entries of the whole tree with IsFlatListing
+
+	jobs chan struct{} // limits the directories read at once to Jobs
+
+	// set when an entry has no TimeField time, see warnTimeFallback()
+	timeFallback         int32
+	isTimeFallbackWarned bool
+
+	countedLinks map[fileID]bool // the hard links added to Totals
+
+	// the totals of the directories to be listed with IsShowDirectorySize,
+	// by path, worked out with those of the directories above them
+	directorySizes sync.Map
+}
+
+// NewLister returns a Lister that writes to out (os.Stdout if nil)
+func NewLister(options Options, out io.Writer) *Lister {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &Lister{Options: options, Out: out}
+}
+
+// messageOut is where warnings and errors are printed: os.Stderr when the
+// output is machine readable so they don't corrupt it, otherwise l.Out
+func (l *Lister) messageOut() io.Writer {
+	if l.IsMachineReadable() {
+		return os.Stderr
+	}
+	return l.Out
+}
+
+// printWarnings prints the Warnings of d found so far
+func (l *Lister) printWarnings(d *Directory) {
+	for _, x := range d.Warnings {
+		fmt.Fprintln(l.messageOut(), x)
+	}
+	d.Warnings = nil
+}
+
+// walkState is what a recursive listing passes down to each subdirectory
+type walkState struct {
+	// path of the directory relative to where the listing started
+	relativePath []string
+	ignores      *ignoreList // with IsHonourIgnoreFiles
+
+	// with IsFollowLinks, the directories from the start directory down to
+	// this one, to detect loops, and the target if this one is a link
+	ancestors  []ancestor
+	linkTarget string
+
+	// with IsOneFileSystem, the device of the start directory
+	device    uint64
+	hasDevice bool
+
+	// when adding up the size of a directory for IsShowDirectorySize
+	isSizing bool
+}
+
+type ancestor struct {
+	id   fileID
+	path string
+}
+
+func (w walkState) child(name string) walkState {
+	relativePath := make([]string, len(w.relativePath)+1)
+	copy(relativePath, w.relativePath)
+	relativePath[len(w.relativePath)] = name
+	child := w
+	child.relativePath = relativePath
+	child.linkTarget = ""
+	return child
+}
+
+// ReadDirectory reads directory and returns the entries matching any of the
+// patterns (or all entries if IsMatchAllFiles is set), filtered and sorted
+// according to the options
+func (l *Lister) ReadDirectory(directory string,
+	patterns []string) (*Directory, error) {
+	return l.readDirectory(directory, patterns, l.newWalkState(directory))
+}
+
+// newWalkState returns the walkState of the directory where a listing
+// starts
+func (l *Lister) newWalkState(directory string) walkState {
+	w := walkState{}
+	if l.IsHonourIgnoreFiles {
+		w.ignores = loadIgnoreFiles(directory,
+			loadAncestorIgnoreFiles(directory))
+	}
+	if l.IsFollowLinks || l.IsOneFileSystem {
+		if info, err := os.Stat(directory); err == nil {
+			if id, ok := getFileID(directory, info); ok && l.IsFollowLinks {
+				w.ancestors = []ancestor{{id, directory}}
+			}
+			w.device, w.hasDevice = getDeviceID(info)
+		}
+	}
+	return w
+}
+
+// childWalkState returns the walkState of the subdirectory pathName, or an
+// error if it is a link back to one of the directories above it
+func (l *Lister) childWalkState(w walkState,
+	pathName string) (walkState, error) {
+	child := w.child(filepath.Base(pathName))
+	if l.IsHonourIgnoreFiles {
+		child.ignores = loadIgnoreFiles(pathName, w.ignores)
+	}
+	if l.IsFollowLinks {
+		info, err := os.Stat(pathName)
+		if err != nil {
+			return child, err
+		}
+		if id, ok := getFileID(pathName, info); ok {
+			for _, x := range w.ancestors {
+				if x.id == id {
+					return child, fmt.Errorf("Loop detected: %s leads back "+
+						"to %s, not followed", pathName, x.path)
+				}
+			}
+			child.ancestors = append(w.ancestors[:len(w.ancestors):len(w.ancestors)],
+				ancestor{id, pathName})
+		}
+		if link, err := util.Readlink(pathName); err == nil {
+			child.linkTarget = link
+		}
+	}
+	return child, nil
+}
+
+// isIgnored tells whether pathName is ignored by the .gitignore, .ignore
+// and .git/info/exclude files. The .git directory itself is always ignored.
+func (l *Lister) isIgnored(w walkState, pathName, name string,
+	isDir bool) bool {
+	if name == ".git" && isDir {
+		return true
+	}
+	return w.ignores.isIgnored(pathName, isDir, func(pattern, name string) bool {
+		if l.IsIgnoreFilenameCase {
+			pattern = FoldCase(pattern, l.Locale)
+			name = FoldCase(name, l.Locale)
+		}
+		matched, err := filepath.Match(pattern, name)
+		return err == nil && matched
+	})
+}
+
+// isMatchingPath tells whether the entry name in the directory of w matches
+// one of the PathPatterns
+func (l *Lister) isMatchingPath(w walkState, name string) bool {
+	path := w.child(name).relativePath
+	for _, x := range l.PathPatterns {
+		if x.Match(path, l.matchSegment) {
+			return true
+		}
+	}
+	return false
+}
+
+// depth returns the depth of the entries of the directory of w, the entries
+// of the directory where the listing starts being at depth 1
+func (w walkState) depth() int {
+	return len(w.relativePath) + 1
+}
+
+// isDescendable tells whether a subdirectory must be read: always with /s
+// unless that would go past MaxDepth, otherwise only if one of the
+// PathPatterns can match below it
+func (l *Lister) isDescendable(w walkState, name string) bool {
+	if w.isSizing {
+		return true
+	}
+	if l.MaxDepth > 0 && w.depth() >= l.MaxDepth {
+		return false
+	}
+	if l.IsRecurseSubDirectory {
+		return true
+	}
+	path := w.child(name).relativePath
+	for _, x := range l.PathPatterns {
+		if x.CanMatchBelow(path, l.matchSegment) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMatchingAny tells whether name matches one of the patterns, a bad
+// /posix pattern simply never matches
+func (l *Lister) isMatchingAny(patterns []string, name string) bool {
+	for _, x := range patterns {
+		if matched, err := l.matchName(x, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegment is matchName for PathPattern, a bad /posix pattern simply
+// never matches
+func (l *Lister) matchSegment(pattern, name string) bool {
+	if l.IsIgnoreFilenameCase {
+		name = FoldCase(name, l.Locale)
+	}
+	matched, err := l.matchName(pattern, name)
+	return err == nil && matched
+}
+
+// readBatchSize is how many entries are read at a time, so that huge
+// directories are never read all at once
+const readBatchSize = 1024
+
+// readDirectory returns the filtered and sorted entries of directory
+func (l *Lister) readDirectory(directory string, patterns []string,
+	w walkState) (*Directory, error) {
+	d := &Directory{Path: directory, LinkTarget: w.linkTarget}
+	err := l.readEntries(d, patterns, w, func(infos []util.PathInfo) error {
+		for _, x := range infos {
+			d.Infos = append(d.Infos, l.retainedInfo(x))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	l.sortInfos(d.Infos)
+	return d, nil
+}
+
+// readEntries reads the directory d.Path readBatchSize entries at a time
+// and calls found() with those of each batch that pass the filters, in
+// the order they are read. The counts and the subdirectories of d are
+// updated as the entries are read.
+func (l *Lister) readEntries(d *Directory, patterns []string, w walkState,
+	found func(infos []util.PathInfo) error) error {
+	f, err := os.Open(d.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	caseFolder := newCollator(l.Locale)
+	for {
+		entries, readErr := f.ReadDir(readBatchSize)
+		infos := make([]util.PathInfo, 0, len(entries))
+		for _, x := range entries {
+			info, err := l.filterEntry(d, &lazyInfo{entry: x}, patterns, w,
+				caseFolder)
+			if err != nil {
+				return err
+			}
+			if info != nil {
+				infos = append(infos, info)
+			}
+		}
+		if len(infos) > 0 {
+			if err = found(infos); err != nil {
+				return err
+			}
+		}
+		if readErr == io.EOF {
+			return nil
+		} else if readErr != nil {
+			return readErr
+		}
+	}
+}
+
+// filterEntry returns the PathInfo of x if it must be listed, nil if not,
+// and adds it to the counts of d
+func (l *Lister) filterEntry(d *Directory, x *lazyInfo, patterns []string,
+	w walkState, caseFolder collator) (util.PathInfo, error) {
+	var err error
+	name := x.Name()
+	if name == "." || name == ".." {
+		return nil, nil
+	}
+	pathName := filepath.Join(d.Path, name)
+	target := name
+	if l.IsIgnoreFilenameCase {
+		target = caseFolder.fold(name)
+	}
+	isDir := x.IsDir()
+	var targetInfo os.FileInfo = x
+	if l.IsFollowLinks && x.entry.Type()&os.ModeSymlink == os.ModeSymlink {
+		// Like find -L, a link to a directory is a directory
+		if info, err := os.Stat(pathName); err == nil {
+			targetInfo = info
+			isDir = info.IsDir()
+		}
+	}
+	isIgnored := false
+	if l.IsHonourIgnoreFiles {
+		isIgnored = l.isIgnored(w, pathName, name, isDir)
+		if isIgnored && (!l.IsShowIgnoredOnly || name == ".git") {
+			return nil, nil
+		}
+	}
+	if isDir {
+		if l.isMatchingAny(l.ExcludeDirectoryPatterns, target) {
+			// pruned: neither listed nor descended into
+			return nil, nil
+		}
+		// The sizes leave out what is in the directories that are not
+		// shown, like the files
+		if w.isSizing && l.isHiddenDirectory(pathName) {
+			return nil, nil
+		}
+		// Everything inside an ignored directory is ignored, which is
+		// shown by listing the directory itself
+		if !isIgnored && l.isDescendable(w, name) &&
+			!(l.IsOneFileSystem &&
+				l.isOtherFileSystem(w, pathName, targetInfo)) {
+			d.SubDirectories = append(d.SubDirectories, pathName)
+		}
+		if l.IsExcludeDirectory {
+			return nil, nil
+		}
+	} else if (l.IsShowDirectoryOnly && !w.isSizing) ||
+		l.isMatchingAny(l.ExcludeFilePatterns, target) {
+		return nil, nil
+	}
+
+	if !l.isTimeSelected(pathName, x) {
+		return nil, nil
+	}
+
+	// The size of a directory means nothing, they are not filtered by size
+	if !isDir && !l.isSizeSelected(x) {
+		return nil, nil
+	}
+
+	if l.IsMultiLinkOnly && (isDir || linkCount(x) < 2) {
+		return nil, nil
+	}
+
+	if l.IsShowIgnoredOnly && !isIgnored {
+		return nil, nil
+	}
+
+	if w.depth() < l.MinDepth && !w.isSizing {
+		return nil, nil
+	}
+
+	matched := l.IsMatchAllFiles && len(l.PathPatterns) == 0
+	if !matched && len(l.PathPatterns) > 0 {
+		matched = l.isMatchingPath(w, name)
+	}
+	if !matched {
+		for _, y := range patterns {
+			if matched, err = l.matchName(y, target); err != nil {
+				return nil, err
+			}
+			if matched {
+				break
+			}
+		}
+	}
+	if !matched {
+		return nil, nil
+	}
+
+	if skip, err := l.isExcludedByAttributes(d, pathName, name,
+		isDir); err != nil {
+		return nil, err
+	} else if skip {
+		return nil, nil
+	}
+
+	info := l.newEntryInfo(d, x, pathName, isDir, patterns, w)
+	if isDir {
+		d.DirectoriesCount++
+	} else {
+		d.FilesCount++
+		d.FilesSize += x.Size()
+		d.AllocatedSize += allocatedSize(x)
+		if link, ok := newHardLink(pathName, x); ok {
+			d.hardLinks = append(d.hardLinks, link)
+		}
+	}
+	if !isDir || directorySummary(info) != nil {
+		size := l.entrySize(info)
+		if allocated := allocatedSize(info); l.SpaceMode == SpaceBoth &&
+			allocated > size {
+			// Both are shown with the same width
+			size = allocated
+		}
+		if size > d.MaxSize {
+			d.MaxSize = size
+		}
+	}
+	if len(name) > d.MaxNameLen {
+		d.MaxNameLen = len(name)
+	}
+	return info, nil
+}
+
+// newEntryInfo returns the PathInfo of the entry x of d, pathName, with its
+// TimeField time and, for a directory with IsShowDirectorySize, the total
+// of what is below it
+func (l *Lister) newEntryInfo(d *Directory, x os.FileInfo, pathName string,
+	isDir bool, patterns []string, w walkState) util.PathInfo {
+	var info util.PathInfo = util.NewPathInfo(x, pathName)
+	var extended *extendedInfo
+	if l.TimeField != 0 && l.TimeField != WriteTime {
+		extended = &extendedInfo{PathInfo: info,
+			time: l.selectedTime(pathName, x)}
+	}
+	if isDir && l.IsShowDirectorySize && !w.isSizing {
+		if extended == nil {
+			extended = &extendedInfo{PathInfo: info}
+		}
+		summary := l.directorySize(d, pathName, patterns, w)
+		extended.directory = &summary
+	}
+	if extended != nil {
+		return extended
+	}
+	return info
+}
+
+// isHiddenDirectory tells whether the directory pathName is left out by
+// IsExcludeHiddenFiles, as a hidden or system one
+func (l *Lister) isHiddenDirectory(pathName string) bool {
+	if !l.IsExcludeHiddenFiles || l.IsShowDirectoryOnly {
+		return false
+	}
+	if hidden, err := util.IsHiddenFile(pathName, true); err != nil ||
+		hidden {
+		return true
+	}
+	system, err := util.IsSystemFile(pathName)
+	return err != nil || system
+}
+
+// isExcludedByAttributes applies the hidden/system and read-only options,
+// the warnings are added to d
+func (l *Lister) isExcludedByAttributes(d *Directory, pathName, name string,
+	isDir bool) (bool, error) {
+	if l.IsExcludeHiddenFiles || l.IsShowHiddenFilesOnly {
+		if hidden, err1 := util.IsHiddenFile(pathName, true); err1 != nil {
+			// the error "The filename, directory name,
+			// or volume label syntax is incorrect." will occur
+			// if the directory is somehow corrupted, but want to
+			// continue anyway
+			d.warn("Warning \"%s\": %v", pathName, err1)
+			return true, nil
+		} else if hidden {
+			if l.IsExcludeHiddenFiles && !(isDir && l.IsShowDirectoryOnly) {
+				// Follow "dir /ad" to show hidden directories
+				return true, nil
+			}
+		} else {
+			// hack hack hack: also show system files
+			if system, err2 := util.IsSystemFile(pathName); err2 != nil {
+				return false, err2
+			} else if system && !(isDir && l.IsShowDirectoryOnly) {
+				d.warn("system: %s", name)
+				// Follow "dir /ah " to show system directories
+				if l.IsExcludeHiddenFiles {
+					return true, nil
+				}
+			}
+			if l.IsShowHiddenFilesOnly {
+				return true, nil
+			}
+		}
+	}
+	if l.IsExcludeReadOnlyFiles || l.IsShowReadOnlyFilesOnly {
+		if readonly, err1 := util.IsReadOnlyFile(pathName); err1 != nil {
+			// the error "The filename, directory name,
+			// or volume label syntax is incorrect." will occur
+			// if the directory is somehow corrupted, but want to
+			// continue anyway
+			d.warn("Warning \"%s\": %v", pathName, err1)
+			return true, nil
+		} else if readonly {
+			if l.IsExcludeReadOnlyFiles {
+				return true, nil
+			}
+		} else if l.IsShowReadOnlyFilesOnly {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// List prints the listing of directory (and of its subdirectories if
+// IsRecurseSubDirectory or PathPatterns are set) followed by a summary line
+// per directory
+func (l *Lister) List(directory string, patterns []string) error {
+	defer l.warnTimeFallback()
+	if l.isParallel() && l.jobs == nil {
+		l.jobs = make(chan struct{}, l.Jobs)
+	}
+	if !l.IsFlatListing {
+		return l.list(directory, patterns, l.newWalkState(directory))
+	}
+	l.flat = &Directory{Path: directory}
+	if err := l.list(directory, patterns, l.newWalkState(directory)); err != nil {
+		return err
+	}
+	return l.printFlatListing()
+}
+
+func (l *Lister) list(directory string, patterns []string,
+	w walkState) error {
+	if l.isStreamed() {
+		d, err := l.streamDirectory(directory, patterns, w)
+		if err != nil {
+			return err
+		}
+		return l.listSubDirectories(d, patterns, w)
+	}
+	d, err := l.readDirectory(directory, patterns, w)
+	if err != nil {
+		return err
+	}
+	return l.listDirectory(d, patterns, w)
+}
+
+// listDirectory prints d, which has already been read, then lists its
+// subdirectories
+func (l *Lister) listDirectory(d *Directory, patterns []string,
+	w walkState) error {
+	l.printWarnings(d)
+	l.countLinks(d)
+	if l.IsFlatListing {
+		l.addToFlatListing(d)
+	} else if err := l.printDirectory(d); err != nil {
+		return err
+	}
+	return l.listSubDirectories(d, patterns, w)
+}
+
+// listSubDirectories lists the subdirectories of d in order, reading up to
+// Jobs of them ahead in other goroutines when isParallel() so that only
+// that many are kept in memory at each level
+func (l *Lister) listSubDirectories(d *Directory, patterns []string,
+	w walkState) error {
+	if l.isParallel() {
+		pending := make([]*pendingDirectory, 0, l.Jobs)
+		next := 0
+		for i := range d.SubDirectories {
+			for ; next < len(d.SubDirectories) && next < i+l.Jobs; next++ {
+				pending = append(pending,
+					l.startReading(d.SubDirectories[next], patterns, w))
+			}
+			x := pending[0]
+			pending[0] = nil
+			pending = pending[1:]
+			<-x.done
+			err := x.err
+			if err == nil {
+				err = l.listDirectory(x.d, patterns, x.w)
+			}
+			if err != nil {
+				fmt.Fprintln(l.messageOut(), err)
+			}
+		}
+	} else {
+		for _, x := range d.SubDirectories {
+			child, err := l.childWalkState(w, x)
+			if err == nil {
+				err = l.list(x, patterns, child)
+			}
+			if err != nil {
+				fmt.Fprintln(l.messageOut(), err)
+				continue
+			}
+		}
+	}
+
+	l.addToTotals(d)
+	return nil
+}
+
+// printDirectory prints the entries of d followed by its summary line
+func (l *Lister) printDirectory(d *Directory) error {
+	if l.IsMachineReadable() {
+		return l.writeRecords(d.Infos)
+	}
+	l.printLines(l.Lines(d))
+	l.printDirectorySummary(d)
+	return nil
+}
+
+func (l *Lister) printDirectorySummary(d *Directory) {
+	if d.FilesCount == 0 && d.DirectoriesCount == 0 {
+		return
+	}
+	directory := d.Path
+	relativeDirectory := directory
+	if strings.HasPrefix(directory,
+		filepath.Dir(l.CurrentWorkingDirectory)) &&
+		len(directory) > l.DisplayDirStart {
+		relativeDirectory = directory[l.DisplayDirStart:]
+		if strings.IndexByte(relativeDirectory, os.PathSeparator) < 0 {
+			relativeDirectory = fmt.Sprintf(".%c%s", os.PathSeparator,
+				relativeDirectory)
+		}
+	}
+	if d.LinkTarget != "" {
+		relativeDirectory += " [followed link to " + d.LinkTarget + "]"
+	}
+	fmt.Fprintln(l.Out)
+	if d.FilesCount == 1 && !l.IsBareDisplayFormat {
+		fmt.Fprintf(l.Out, "%"+strconv.Itoa(cmd.MaxFileSizeWidth+5)+
+			"s Only one file in %s\n", "", relativeDirectory)
+	} else if d.FilesCount > 1 {
+		size := l.FormatSize(d.FilesSize)
+		if l.isShowingAllocated() {
+			bytesWord := ""
+			if l.IsSizeInBytes() {
+				bytesWord = " bytes"
+			}
+			size = fmt.Sprintf("%s%s, %s%s allocated", size, bytesWord,
+				l.FormatSize(d.AllocatedSize), bytesWord)
+		} else if l.IsSizeInBytes() {
+			// the size is always followed by its number of bytes, which is
+			// the size itself when it is not grouped
+			if size == strconv.FormatInt(d.FilesSize, 10) {
+				size += " bytes"
+			} else {
+				size += fmt.Sprintf(" (%d bytes)", d.FilesSize)
+			}
+		}
+		fmt.Fprintf(l.Out, "%"+strconv.Itoa(cmd.MaxFileSizeWidth+22)+
+			"s %s\n", fmt.Sprintf("%d Files %s", d.FilesCount, size),
+			relativeDirectory)
+	} else if d.DirectoriesCount == 1 {
+		fmt.Fprintf(l.Out, "%"+cmd.MaxFileSizeWidthText+
+			"s Only one directory in %s\n", "", relativeDirectory)
+	} else if d.DirectoriesCount > 1 {
+		fmt.Fprintf(l.Out, "%"+cmd.MaxFileSizeWidthText+
+			"d directories in %s\n",
+			d.DirectoriesCount, relativeDirectory)
+	}
+	if l.IsRecursive() {
+		fmt.Fprintln(l.Out)
+	}
+}
+
+// addToFlatListing keeps the entries of d to be sorted and printed with
+// those of the other directories once the whole tree has been read
+func (l *Lister) addToFlatListing(d *Directory) {
+	l.flat.Infos = append(l.flat.Infos, d.Infos...)
+	if d.MaxSize > l.flat.MaxSize {
+		l.flat.MaxSize = d.MaxSize
+	}
+	for _, x := range d.Infos {
+		if n := len(l.relativePathName(x.PathName())); n > l.flat.MaxNameLen {
+			l.flat.MaxNameLen = n
+		}
+	}
+}
+
+// printFlatListing sorts the entries of the whole tree together and prints
+// them with their path relative to the start directory, so /h and /t apply
+// to the combined list. Only the total line follows them.
+func (l *Lister) printFlatListing() error {
+	d := l.flat
+	l.flat = nil
+	l.sortInfos(d.Infos)
+	if l.IsMachineReadable() {
+		return l.writeRecords(d.Infos)
+	}
+	isShowPartialPath := l.IsShowPartialPath
+	l.IsShowPartialPath = !l.IsShowFullPath
+	l.printLines(l.Lines(d))
+	l.IsShowPartialPath = isShowPartialPath
+	fmt.Fprintln(l.Out)
+	return nil
+}
+
+// listAllFiles sets the options to list everything in the directories named
+// explicitly except the hidden files, as when no pattern is given
+func (l *Lister) listAllFiles() {
+	l.IsMatchAllFiles = true
+	l.IsExcludeHiddenFiles = true
+}
+
+// ListPaths prints the listing of an explicit list of absolute path names,
+// followed by the content of those that are directories
+func (l *Lister) ListPaths(pathList []string) error {
+	defer l.warnTimeFallback()
+	// because the paths could be anywhere in the system, must show at least
+	// part of the path to distiguish between dir1/abc and dir2/abc
+	l.IsShowPartialPath = !l.IsShowFullPath
+
+	// The directories are listed with all their files, which is also what
+	// their size adds up with IsShowDirectorySize
+	l.listAllFiles()
+	patterns := []string{}
+
+	l.Totals.FilesCount, l.Totals.FilesSize = 0, 0
+	l.Totals.AllocatedSize = 0
+	d := &Directory{Infos: make([]util.PathInfo, 0, len(pathList))}
+	for _, pathName := range pathList {
+		info, err := os.Lstat(pathName)
+		if err != nil {
+			fmt.Fprintf(l.messageOut(), "%s\n", err)
+		} else {
+			if info.IsDir() {
+				d.SubDirectories = append(d.SubDirectories, pathName)
+			}
+			// Like in a listing, a directory is listed even if it is not
+			// shown itself
+			if !l.isTimeSelected(pathName, info) {
+				continue
+			}
+			d.Infos = append(d.Infos, l.newEntryInfo(d, info, pathName,
+				info.IsDir(), patterns,
+				l.newWalkState(filepath.Dir(pathName))))
+			l.Totals.FilesCount++
+			if x, ok := newHardLink(pathName, info); ok && l.isCounted(x) {
+				continue
+			}
+			l.Totals.FilesSize += info.Size()
+			l.Totals.AllocatedSize += allocatedSize(info)
+		}
+	}
+	l.printWarnings(d)
+
+	if l.IsMachineReadable() {
+		if err := l.writeRecords(d.Infos); err != nil {
+			return err
+		}
+	} else {
+		if l.IsUnixStyleListing {
+			l.printLines(l.getUnixLongFileListing(d.Infos))
+		} else {
+			l.printLines(l.getWindowsLongFileListing(d.Infos,
+				cmd.MaxFileSizeWidth))
+		}
+		fmt.Fprintln(l.Out)
+	}
+
+	l.IsShowPartialPath = false
+	for _, x := range d.SubDirectories {
+		l.List(x, patterns)
+		if !l.IsMachineReadable() {
+			fmt.Fprintln(l.Out)
+		}
+	}
+	return nil
+}
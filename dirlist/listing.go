@@ -0,0 +1,382 @@
+package dirlist
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tsaost/util"
+	"github.com/tsaost/util/cmd"
+)
+
+// Lines renders the entries of d according to the display format options
+func (l *Lister) Lines(d *Directory) []string {
+	if l.IsWideDisplayFormat {
+		return l.getWideFormatFileListing(d.Infos)
+	} else if l.IsUnixStyleListing {
+		return l.getUnixLongFileListing(d.Infos)
+	}
+	sizeFieldWidth := cmd.MaxFileSizeWidth
+	if d.MaxNameLen > l.WideFormatLineWidth-(cmd.MaxFileSizeWidth+20) {
+		// Try to use cmd.MaxFileSizeWidth, unless maxNameLen is larger
+		// than the available width
+		sizeFieldWidth = len(l.FormatSize(d.MaxSize))
+	}
+	return l.getWindowsLongFileListing(d.Infos, sizeFieldWidth)
+}
+
+// displayedInfo makes cmd.GetUnixLongFileListing() show the entryTime()
+// and the entrySize()
+type displayedInfo struct {
+	util.PathInfo
+	time time.Time
+	size int64
+}
+
+func (x displayedInfo) ModTime() time.Time { return x.time }
+func (x displayedInfo) Size() int64        { return x.size }
+
+// isSharedUnixListing tells whether the ls -l listing of the util cmd
+// package can be used, which is when no time format, time zone, size
+// format or allocated size is asked for
+func (l *Lister) isSharedUnixListing() bool {
+	return l.TimeFormat == "" && l.Location == nil && l.SizeFormat == "" &&
+		l.SizeUnit == "" && l.SpaceMode != SpaceBoth
+}
+
+func (l *Lister) getUnixLongFileListing(infos []util.PathInfo) []string {
+	if !l.isSharedUnixListing() {
+		return l.getFormattedUnixLongFileListing(infos)
+	}
+	displayed := make([]util.PathInfo, len(infos))
+	for i, x := range infos {
+		displayed[i] = displayedInfo{PathInfo: x, time: entryTime(x),
+			size: l.entrySize(x)}
+	}
+	return cmd.GetUnixLongFileListing(displayed, l.IsShowFullPath,
+		l.IsShowPartialPath, l.IsShowNumericUnixFileMode,
+		l.CurrentWorkingDirectory, l.DisplayPathStart)
+}
+
+// getFormattedUnixLongFileListing renders infos like ls -l with the
+// TimeFormat, Location, SizeFormat, SizeUnit and SpaceMode: mode, link
+// count, owner, group, size, time and name, the columns being aligned
+func (l *Lister) getFormattedUnixLongFileListing(
+	infos []util.PathInfo) []string {
+	type row struct {
+		allocated, mode, links, owner, group, size, time, name string
+	}
+	rows := make([]row, len(infos))
+	var allocatedWidth, linksWidth, ownerWidth, groupWidth, sizeWidth int
+	for i, info := range infos {
+		links, owner, group := getOwner(info)
+		r := row{mode: unixModeString(info.Mode()),
+			links: strconv.FormatUint(links, 10), owner: owner, group: group,
+			size: strconv.FormatInt(l.entrySize(info), 10),
+			time: l.formatTime(entryTime(info), true)}
+		if l.SizeFormat != "" || l.SizeUnit != "" {
+			// Like ls, the bytes unless another format is asked for
+			r.size = l.FormatSize(l.entrySize(info))
+		}
+		if l.SpaceMode == SpaceBoth {
+			// Like ls -s, in 1K blocks unless another format is asked for
+			allocated := allocatedSize(info)
+			if l.SizeFormat != "" || l.SizeUnit != "" {
+				r.allocated = l.FormatSize(allocated)
+			} else {
+				r.allocated = strconv.FormatInt((allocated+1023)/1024, 10)
+			}
+		}
+		if l.IsShowNumericUnixFileMode {
+			r.mode = fmt.Sprintf("%04o", unixPermissionBits(info.Mode()))
+		}
+		pathName := info.PathName()
+		if l.IsShowPartialPath {
+			r.name = l.relativePathName(pathName)
+		} else if l.IsShowFullPath {
+			r.name = pathName
+		} else {
+			r.name = info.Name()
+		}
+		if info.Mode()&os.ModeSymlink == os.ModeSymlink {
+			if link, err := util.Readlink(pathName); err == nil {
+				r.name += " -> " + link
+			}
+		}
+		if len(r.allocated) > allocatedWidth {
+			allocatedWidth = len(r.allocated)
+		}
+		if len(r.links) > linksWidth {
+			linksWidth = len(r.links)
+		}
+		if len(r.owner) > ownerWidth {
+			ownerWidth = len(r.owner)
+		}
+		if len(r.group) > groupWidth {
+			groupWidth = len(r.group)
+		}
+		if len(r.size) > sizeWidth {
+			sizeWidth = len(r.size)
+		}
+		rows[i] = r
+	}
+
+	listing := make([]string, len(rows))
+	for i, r := range rows {
+		listing[i] = fmt.Sprintf("%s %*s %-*s %-*s %*s %s %s", r.mode,
+			linksWidth, r.links, ownerWidth, r.owner, groupWidth, r.group,
+			sizeWidth, r.size, r.time, r.name)
+		if allocatedWidth > 0 {
+			listing[i] = fmt.Sprintf("%*s %s", allocatedWidth, r.allocated,
+				listing[i])
+		}
+	}
+	return listing
+}
+
+// unixModeString returns the mode the way ls shows it, e.g. drwxr-sr-x,
+// which is not what os.FileMode.String() does for the type and the setuid,
+// setgid and sticky bits
+func unixModeString(mode os.FileMode) string {
+	b := []byte("-" + mode.Perm().String()[1:])
+	switch {
+	case mode&os.ModeDir != 0:
+		b[0] = 'd'
+	case mode&os.ModeSymlink != 0:
+		b[0] = 'l'
+	case mode&os.ModeNamedPipe != 0:
+		b[0] = 'p'
+	case mode&os.ModeSocket != 0:
+		b[0] = 's'
+	case mode&os.ModeCharDevice != 0:
+		b[0] = 'c'
+	case mode&os.ModeDevice != 0:
+		b[0] = 'b'
+	}
+	special := func(i int, isSet bool, ch byte) {
+		if !isSet {
+			return
+		}
+		if b[i] == 'x' {
+			b[i] = ch
+		} else {
+			b[i] = ch - 'a' + 'A'
+		}
+	}
+	special(3, mode&os.ModeSetuid != 0, 's')
+	special(6, mode&os.ModeSetgid != 0, 's')
+	special(9, mode&os.ModeSticky != 0, 't')
+	return string(b)
+}
+
+// unixPermissionBits returns the mode as the octal number of chmod
+func unixPermissionBits(mode os.FileMode) uint32 {
+	bits := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		bits |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		bits |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		bits |= 01000
+	}
+	return bits
+}
+
+func (l *Lister) getWideFormatFileListing(infos []util.PathInfo) []string {
+	listing := make([]string, 0, len(infos))
+
+	maxLen := 13
+	for _, x := range infos {
+		name := l.wideName(x)
+		lenName := len(name)
+		if x.IsDir() {
+			lenName += 2 // Need to put [..] around directory
+		}
+		if lenName > maxLen {
+			maxLen = lenName
+		}
+	}
+
+	const spaces = "                                                          "
+	const maxSpacesLen = len(spaces)
+
+	maxLen++ // Need at least one space between names
+	if maxLen > maxSpacesLen {
+		maxLen = maxSpacesLen
+	}
+	entriesPerLine := l.WideFormatLineWidth / maxLen
+	if entriesPerLine == 0 {
+		entriesPerLine = 1
+	}
+	i, line := 0, ""
+	for _, x := range infos {
+		name := l.wideName(x)
+		if x.IsDir() {
+			name = "[" + name + "]"
+		}
+		line = line + name
+		i++
+		if i == entriesPerLine {
+			listing = append(listing, line)
+			i, line = 0, ""
+		} else {
+			spacing := maxLen - len(name)
+			if spacing < 0 {
+				spacing = 0
+			} else if spacing > maxSpacesLen {
+				spacing = maxSpacesLen
+			}
+			line += spaces[:spacing]
+		}
+	}
+	if i > 0 {
+		listing = append(listing, line)
+	}
+	return listing
+}
+
+// wideName is the name shown in the wide format, with part of the path if
+// the entries come from different directories
+func (l *Lister) wideName(info util.PathInfo) string {
+	if l.IsShowPartialPath {
+		return l.relativePathName(info.PathName())
+	}
+	return info.Name()
+}
+
+func (l *Lister) getWindowsLongFileListing(infos []util.PathInfo,
+	sizeWidth int) []string {
+	listing := make([]string, len(infos), len(infos))
+	listingFormat := "%s  %" + strconv.Itoa(sizeWidth) + "s %s"
+	if l.SpaceMode == SpaceBoth {
+		// The allocated size follows the apparent size
+		listingFormat = "%s  %" + strconv.Itoa(sizeWidth) + "s %" +
+			strconv.Itoa(sizeWidth) + "s %s"
+	}
+	linksWidth := 0
+	if l.IsShowLinkCount {
+		for _, info := range infos {
+			if n := len(strconv.FormatUint(linkCount(info), 10)); n > linksWidth {
+				linksWidth = n
+			}
+		}
+	}
+	parentDevices := map[string]uint64{}
+	for i, info := range infos {
+		isSymlink := info.Mode()&os.ModeSymlink == os.ModeSymlink
+		name := info.Name()
+		pathName := info.PathName()
+		isDir := info.IsDir()
+		var linkTarget string
+		if isSymlink {
+			if link, err := util.Readlink(pathName); err == nil {
+				linkTarget = " [" + link + "]"
+				if !isDir {
+					// hack hack hack
+					// treat links to directories as directory so <JUNCTION>
+					// will appear in the listing just as under Windows
+					var targetInfo os.FileInfo
+					if targetInfo, err = os.Stat(link); err == nil {
+						isDir = targetInfo.IsDir()
+					}
+				}
+			}
+		}
+
+		if l.IsBareDisplayFormat {
+			displayName := pathName
+			if !l.IsShowFullPath {
+				displayName = l.relativePathName(pathName)
+			}
+			if info.IsDir() && !l.IsShowDirectoryOnly {
+				displayName = "[" + displayName + "]"
+			}
+			if linkTarget != "" {
+				displayName += linkTarget
+			}
+			if l.IsShowQuoteForFileWithSpaces &&
+				strings.Contains(displayName, " ") {
+				displayName = "\"" + displayName + "\""
+			}
+			listing[i] = displayName
+			continue
+		}
+
+		var size, fileCount string
+		summary := directorySummary(info)
+		if summary != nil {
+			size = l.FormatSize(l.entrySize(info))
+			if summary.FilesCount == 1 {
+				fileCount = " (1 file)"
+			} else {
+				fileCount = fmt.Sprintf(" (%d files)", summary.FilesCount)
+			}
+		} else if isDir {
+			if isSymlink {
+				size = "<JUNCTION>    "
+			} else if l.isMountPoint(pathName, info, parentDevices) {
+				size = "<MOUNT>       "
+			} else {
+				size = "<DIR>         "
+			}
+		} else {
+			size = l.FormatSize(l.entrySize(info))
+		}
+
+		displayName := pathName
+		if l.IsShowPartialPath {
+			displayName = l.relativePathName(pathName)
+		} else if !l.IsShowFullPath {
+			displayName = name
+		}
+		if l.IsShowLinkCount {
+			displayName = fmt.Sprintf("%*d %s", linksWidth, linkCount(info),
+				displayName)
+		}
+		if l.SpaceMode == SpaceBoth {
+			allocated := ""
+			if !isDir || summary != nil {
+				allocated = l.FormatSize(allocatedSize(info))
+			}
+			listing[i] = fmt.Sprintf(listingFormat,
+				l.formatTime(entryTime(info), false), size, allocated,
+				displayName+linkTarget+fileCount)
+		} else {
+			listing[i] = fmt.Sprintf(listingFormat,
+				l.formatTime(entryTime(info), false), size,
+				displayName+linkTarget+fileCount)
+		}
+	}
+	return listing
+}
+
+// relativePathName trims the current working directory from pathName the
+// same way as the partial path display
+func (l *Lister) relativePathName(pathName string) string {
+	if strings.HasPrefix(pathName, l.CurrentWorkingDirectory) {
+		return pathName[l.DisplayPathStart:]
+	}
+	return pathName
+}
+
+// omittedLine replaces the lines left out by /h and /t
+const omittedLine = "..........  ..... .."
+
+// printLines prints listing to l.Out, keeping only the first
+// NumberOfHeadLines or the last NumberOfTailLines lines if either is set
+func (l *Lister) printLines(listing []string) {
+	if l.NumberOfHeadLines != 0 && l.NumberOfHeadLines < len(listing) {
+		listing = append(listing[:l.NumberOfHeadLines], omittedLine)
+	} else if l.NumberOfTailLines != 0 && l.NumberOfTailLines < len(listing) {
+		listing = listing[len(listing)-l.NumberOfTailLines-1:]
+		listing[0] = omittedLine
+	}
+
+	for _, line := range listing {
+		fmt.Fprintln(l.Out, line)
+	}
+}
@@ -0,0 +1,115 @@
+package dirlist
+
+import (
+	"path/filepath"
+)
+
+// The wildcards of a pattern once converted the same way as FindFirstFile()
+// does before calling FsRtlIsNameInExpression()
+const (
+	literal = iota
+	star    // '*' matches zero or more characters
+	dosStar // '*' followed by '.' matches up to the last '.' of the name
+	dosQM   // '?' matches one character, or none before a '.' or the end
+	dosDot  // '.' followed by '?', '*' or the end matches '.' or the end
+)
+
+type wildcardToken struct {
+	kind int
+	ch   rune
+}
+
+func parseWindowsPattern(pattern string) []wildcardToken {
+	runes := []rune(pattern)
+	tokens := make([]wildcardToken, 0, len(runes))
+	for i, x := range runes {
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		switch {
+		case x == '*' && next == '.':
+			tokens = append(tokens, wildcardToken{kind: dosStar})
+		case x == '*':
+			tokens = append(tokens, wildcardToken{kind: star})
+		case x == '?':
+			tokens = append(tokens, wildcardToken{kind: dosQM})
+		case x == '.' && (next == '?' || next == '*' || next == 0):
+			tokens = append(tokens, wildcardToken{kind: dosDot})
+		default:
+			// '[' and everything else are literal characters
+			tokens = append(tokens, wildcardToken{kind: literal, ch: x})
+		}
+	}
+	return tokens
+}
+
+func matchWindowsTokens(tokens []wildcardToken, name []rune) bool {
+	for len(tokens) > 0 {
+		switch tokens[0].kind {
+		case star:
+			for i := 0; i <= len(name); i++ {
+				if matchWindowsTokens(tokens[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case dosStar:
+			limit := len(name)
+			for i := len(name) - 1; i >= 0; i-- {
+				if name[i] == '.' {
+					limit = i
+					break
+				}
+			}
+			for i := 0; i <= limit; i++ {
+				if matchWindowsTokens(tokens[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case dosQM:
+			if len(name) == 0 || name[0] == '.' {
+				for len(tokens) > 0 && tokens[0].kind == dosQM {
+					tokens = tokens[1:]
+				}
+				continue
+			}
+			name = name[1:]
+		case dosDot:
+			if len(name) > 0 {
+				if name[0] != '.' {
+					return false
+				}
+				name = name[1:]
+			}
+		default:
+			if len(name) == 0 || name[0] != tokens[0].ch {
+				return false
+			}
+			name = name[1:]
+		}
+		tokens = tokens[1:]
+	}
+	return len(name) == 0
+}
+
+// MatchWindowsPattern reports whether name matches pattern the way the
+// Windows dir command does it: "*.*" also matches names without a '.', a
+// trailing '.' only matches names without extension, '?' also matches
+// nothing before a '.' and '[' is not special. Case is not ignored.
+func MatchWindowsPattern(pattern, name string) bool {
+	if pattern == "*" || pattern == "*.*" {
+		return true
+	}
+	return matchWindowsTokens(parseWindowsPattern(pattern), []rune(name))
+}
+
+// matchName matches name with the Windows dir wildcards, or with
+// filepath.Match if IsPosixGlob is set
+func (o *Options) matchName(pattern, name string) (bool, error) {
+	if o.IsPosixGlob {
+		return filepath.Match(pattern, name)
+	}
+	return MatchWindowsPattern(pattern, name), nil
+}
@@ -0,0 +1,67 @@
+package dirlist
+
+import (
+	"testing"
+)
+
+func TestMatchWindowsPattern(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*", "a.txt", true},
+		{"*.*", "a.txt", true},
+		{"*.*", "README", true},
+		{"*.*", ".profile", true},
+		{"*.", "README", true},
+		{"*.", "a.txt", false},
+		{"*.", "a.b.c", false},
+		{"a?.txt", "ab.txt", true},
+		{"a?.txt", "a.txt", true},
+		{"a?.txt", "abc.txt", false},
+		{"a??", "a", true},
+		{"a??", "abcd", false},
+		{"*.txt", "a.txt", true},
+		{"*.txt", "a.b.txt", true},
+		{"*.txt", "a.txt.bak", false},
+		{"*.txt", "a.TXT", false},
+		{"*.t*", "a.txt.bak", true}, // DOS_STAR can stop before any dot
+		{"*.b*", "a.txt.bak", true},
+		{"a*b", "ab", true},
+		{"a*b", "a.b", true},
+		{"a*b", "a.bc", false},
+		{"[a].txt", "[a].txt", true},
+		{"[a].txt", "a.txt", false},
+		{"[", "[", true},
+		{"file.", "file", true},
+		{"file.", "file.txt", false},
+	}
+	for _, x := range tests {
+		if got := MatchWindowsPattern(x.pattern, x.name); got != x.want {
+			t.Errorf("MatchWindowsPattern(%q, %q) = %v, want %v",
+				x.pattern, x.name, got, x.want)
+		}
+	}
+}
+
+func TestMatchNamePosixGlob(t *testing.T) {
+	o := &Options{IsPosixGlob: true}
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.*", "README", false},
+		{"[a].txt", "a.txt", true},
+		{"[a].txt", "[a].txt", false},
+	}
+	for _, x := range tests {
+		got, err := o.matchName(x.pattern, x.name)
+		if err != nil || got != x.want {
+			t.Errorf("matchName(%q, %q) = %v, %v, want %v",
+				x.pattern, x.name, got, err, x.want)
+		}
+	}
+	if _, err := o.matchName("[", "["); err == nil {
+		t.Errorf("matchName(\"[\", \"[\") with IsPosixGlob has no error")
+	}
+}
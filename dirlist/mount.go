@@ -0,0 +1,59 @@
+package dirlist
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// isMountPoint tells whether the directory pathName is where a file system
+// is mounted, either because it is listed as such or because it is not on
+// the same device as its parent directory. parentDevices caches the device
+// of the parent directories.
+func (l *Lister) isMountPoint(pathName string, info os.FileInfo,
+	parentDevices map[string]uint64) bool {
+	l.mountPointsOnce.Do(func() {
+		l.mountPoints = readMountPoints()
+	})
+	if absolutePathName, err := filepath.Abs(pathName); err == nil &&
+		l.mountPoints[absolutePathName] {
+		return true
+	}
+
+	device, ok := deviceID(info)
+	if !ok {
+		return false
+	}
+	parent := filepath.Dir(pathName)
+	parentDevice, ok := parentDevices[parent]
+	if !ok {
+		parentInfo, err := os.Stat(parent)
+		if err != nil {
+			return false
+		}
+		if parentDevice, ok = getDeviceID(parentInfo); !ok {
+			return false
+		}
+		parentDevices[parent] = parentDevice
+	}
+	return device != parentDevice
+}
+
+// deviceID returns the device of the file system info is on, which a
+// compactInfo keeps for the directories since it has no Sys()
+func deviceID(info os.FileInfo) (uint64, bool) {
+	if x, ok := info.(*compactInfo); ok {
+		return x.device, x.hasDevice
+	}
+	return getDeviceID(info)
+}
+
+// isOtherFileSystem tells whether the directory pathName must not be
+// descended into with IsOneFileSystem
+func (l *Lister) isOtherFileSystem(w walkState, pathName string,
+	info os.FileInfo) bool {
+	if device, ok := getDeviceID(info); ok && w.hasDevice &&
+		device != w.device {
+		return true
+	}
+	return l.isMountPoint(pathName, info, map[string]uint64{})
+}
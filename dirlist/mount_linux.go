@@ -0,0 +1,48 @@
+package dirlist
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readMountPoints returns the mount points listed in /proc/self/mountinfo,
+// which also has the bind mounts that don't change the device
+func readMountPoints() map[string]bool {
+	mountPoints := map[string]bool{}
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return mountPoints
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 4 {
+			mountPoints[unescapeMountPoint(fields[4])] = true
+		}
+	}
+	return mountPoints
+}
+
+// unescapeMountPoint decodes the \040 style octal escapes used for spaces,
+// tabs, newlines and backslashes in mountinfo
+func unescapeMountPoint(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if ch, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(ch))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
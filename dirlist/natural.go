@@ -0,0 +1,50 @@
+package dirlist
+
+import (
+	"strings"
+)
+
+func isDigit(ch byte) bool {
+	return '0' <= ch && ch <= '9'
+}
+
+// naturalCompare compares a and b ignoring case, except that runs of
+// digits are compared by their numeric value so that "file2.log" comes
+// before "file10.log" and "v1.9" before "v1.10"
+func naturalCompare(a, b string) int {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			iStart, jStart := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			aDigits := strings.TrimLeft(a[iStart:i], "0")
+			bDigits := strings.TrimLeft(b[jStart:j], "0")
+			// No need to convert to a number (which could overflow), the
+			// longer one is larger and strings of the same length compare
+			// the same way as their values
+			if len(aDigits) != len(bDigits) {
+				return compareInt64(int64(len(aDigits)), int64(len(bDigits)))
+			}
+			if c := strings.Compare(aDigits, bDigits); c != 0 {
+				return c
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return compareInt64(int64(a[i]), int64(b[j]))
+		}
+		i++
+		j++
+	}
+	if c := compareInt64(int64(len(a)-i), int64(len(b)-j)); c != 0 {
+		return c
+	}
+	// Same value, so "file01" and "file1" are ordered by plain comparison
+	return strings.Compare(a, b)
+}
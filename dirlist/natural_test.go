@@ -0,0 +1,34 @@
+package dirlist
+
+import (
+	"testing"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"file2.log", "file10.log", -1},
+		{"file10.log", "file2.log", 1},
+		{"v1.9", "v1.10", -1},
+		{"a1b2", "a1b10", -1},
+		{"a2b1", "a10b1", -1},
+		{"a10b2", "a10b10", -1},
+		{"x9y", "x09y", 1}, // same value, plain comparison
+		{"file01", "file1", -1},
+		{"file1", "file1", 0},
+		{"File2", "file10", -1},
+		{"abc", "ABC", 0},
+		{"10", "9a", 1},
+		{"a", "a1", -1},
+		{"1", "a", -1},
+		{"99999999999999999999999", "100000000000000000000000", -1},
+	}
+	for _, x := range tests {
+		if got := naturalCompare(x.a, x.b); got != x.want {
+			t.Errorf("naturalCompare(%q, %q) = %d, want %d", x.a, x.b, got,
+				x.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package dirlist
+
+import (
+	"time"
+)
+
+// Options holds every setting that affects how a directory is read, filtered,
+// sorted and rendered. The zero value is usable but NewOptions() gives the
+// same defaults as the xdir command.
+type Options struct {
+	// SortKeys is the sort order, DefaultSortKeys if nil
+	SortKeys   []SortKey
+	IsUnsorted bool // /o:none, print the entries as they are read
+
+	IsShowHiddenFilesOnly, IsExcludeHiddenFiles     bool
+	IsShowReadOnlyFilesOnly, IsExcludeReadOnlyFiles bool
+	IsShowDirectoryOnly, IsExcludeDirectory         bool
+	IsRecurseSubDirectory, IsNoCommaSeparator       bool
+	IsShowFullPath, IsShowPartialPath               bool
+	IsBareDisplayFormat, IsWideDisplayFormat        bool
+	IsMatchAllFiles, IsIgnoreFilenameCase           bool
+	IsUnixStyleListing, IsShowNumericUnixFileMode   bool
+	IsShowQuoteForFileWithSpaces                    bool
+	IsPosixGlob                                     bool // filepath.Match
+	IsHonourIgnoreFiles, IsShowIgnoredOnly          bool // .gitignore
+	IsFollowLinks                                   bool // like find -L
+	IsOneFileSystem                                 bool // like find -xdev
+	IsFlatListing                                   bool // sort the whole tree
+	IsJSONFormat, IsNDJSONFormat                    bool
+	IsCSVFormat, IsTSVFormat                        bool
+	Columns                                         []string // for /csv and /tsv
+
+	// PathPatterns are matched against the path relative to the directory
+	// given to Lister.List(), which only descends into the directories
+	// where they can still match
+	PathPatterns []PathPattern
+
+	// ExcludeFilePatterns and ExcludeDirectoryPatterns are matched against
+	// the names (case folded if IsIgnoreFilenameCase is set). Excluded
+	// directories are not descended into either.
+	ExcludeFilePatterns, ExcludeDirectoryPatterns []string
+
+	// Locale is used to sort names and to fold their case if
+	// IsIgnoreFilenameCase is set, see LocaleFromEnvironment()
+	Locale string
+
+	// Jobs is how many directories can be read at the same time, and read
+	// ahead of the one being printed at each level, when listing
+	// subdirectories. The output is the same whatever its value.
+	Jobs int
+
+	// MaxDepth stops the recursion at that depth (0 for no limit) and
+	// MinDepth omits the entries above that depth, the entries of the start
+	// directory being at depth 1
+	MaxDepth, MinDepth int
+
+	NumberOfHeadLines, NumberOfTailLines int
+	WideFormatLineWidth                  int
+
+	// FileCutoffTime and FileUntilTime only list the entries whose
+	// DateFilterField time (WriteTime if 0) is at or after FileCutoffTime
+	// and before FileUntilTime, if they are set
+	FileCutoffTime, FileUntilTime time.Time
+	DateFilterField               byte
+
+	// TimeField is the time that is displayed and sorted on, and filtered
+	// on if DateFilterField is not set (WriteTime if 0)
+	TimeField byte
+
+	// TimeFormat is how times are shown, see ParseTimeFormat(), and
+	// Location is the time zone they are shown in (the local one if nil)
+	TimeFormat string
+	Location   *time.Location
+
+	// SizeFormat is how sizes are shown, see FormatSize(), unless SizeUnit
+	// (like "M" or "MiB") is set. IsNoCommaSeparator is the same as
+	// SizeFormatRaw.
+	SizeFormat, SizeUnit string
+
+	// SpaceMode selects the apparent size (the default), the allocated
+	// size or both, see SpaceApparent
+	SpaceMode string
+
+	// IsShowLinkCount adds the number of hard links to the dir listing (ls
+	// always shows it) and IsMultiLinkOnly only lists the files that have
+	// more than one
+	IsShowLinkCount, IsMultiLinkOnly bool
+
+	// IsShowDirectorySize shows the total size of the files below each
+	// directory instead of <DIR>, which is also what the size sorts on
+	IsShowDirectorySize bool
+
+	// IsDiskUsageTree prints the total size of each directory as a tree,
+	// see ListTree(), down to TreeDepth (0 for all). The directories
+	// smaller than TreeMinSize or TreeMinPercent of their parent are
+	// grouped in one line.
+	IsDiskUsageTree bool
+	TreeDepth       int
+	TreeMinSize     int64
+	TreeMinPercent  float64
+
+	// SizeRanges only lists the files whose size is in all of them
+	SizeRanges []SizeRange
+
+	// CurrentWorkingDirectory, DisplayPathStart and DisplayDirStart are
+	// used to trim the leading part of path names for partial path display
+	// (see cmd.ExtractStartDirectory)
+	CurrentWorkingDirectory           string
+	DisplayPathStart, DisplayDirStart int
+}
+
+// IsRecursive tells whether subdirectories are listed
+func (o *Options) IsRecursive() bool {
+	return o.IsRecurseSubDirectory || len(o.PathPatterns) > 0
+}
+
+// DefaultJobs is the default for Jobs. Reading directories mostly waits on
+// the disk or the network, not on the CPU, so it doesn't depend on the
+// number of CPUs.
+const DefaultJobs = 8
+
+// NewOptions returns the default options used by xdir
+func NewOptions() Options {
+	return Options{
+		WideFormatLineWidth: 80,
+		Jobs:                DefaultJobs,
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package dirlist
+
+import (
+	"os"
+)
+
+// getOwner has no owner and group to show outside Unix
+func getOwner(info os.FileInfo) (links uint64, owner, group string) {
+	return 1, "", ""
+}
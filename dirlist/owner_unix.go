@@ -0,0 +1,44 @@
+//go:build unix
+
+package dirlist
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// userNames and groupNames cache the lookups of the ids of owners
+var userNames, groupNames sync.Map
+
+// getOwner returns the link count, the owner and the group of info for the
+// ls -l listing, the ids are shown when they have no name
+func getOwner(info os.FileInfo) (links uint64, owner, group string) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 1, "", ""
+	}
+	uid := strconv.FormatUint(uint64(stat.Uid), 10)
+	gid := strconv.FormatUint(uint64(stat.Gid), 10)
+	if x, ok := userNames.Load(uid); ok {
+		owner = x.(string)
+	} else {
+		owner = uid
+		if u, err := user.LookupId(uid); err == nil {
+			owner = u.Username
+		}
+		userNames.Store(uid, owner)
+	}
+	if x, ok := groupNames.Load(gid); ok {
+		group = x.(string)
+	} else {
+		group = gid
+		if g, err := user.LookupGroupId(gid); err == nil {
+			group = g.Name
+		}
+		groupNames.Store(gid, group)
+	}
+	return uint64(stat.Nlink), owner, group
+}
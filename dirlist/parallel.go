@@ -0,0 +1,33 @@
+package dirlist
+
+// pendingDirectory is a subdirectory being read in another goroutine
+type pendingDirectory struct {
+	done chan struct{} // closed once d or err is set
+	d    *Directory
+	w    walkState
+	err  error
+}
+
+// isParallel tells whether subdirectories are read ahead in other
+// goroutines. Streamed listings print while reading so they can't be.
+func (l *Lister) isParallel() bool {
+	return l.Jobs > 1 && !l.isStreamed()
+}
+
+// startReading reads the subdirectory pathName of the directory of w in a
+// new goroutine, at most Jobs of them read at the same time. Only the
+// reading and the filtering is done there, the printing is left to the
+// caller so the output is in the same order as a sequential listing.
+func (l *Lister) startReading(pathName string, patterns []string,
+	w walkState) *pendingDirectory {
+	p := &pendingDirectory{done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		l.jobs <- struct{}{}
+		defer func() { <-l.jobs }()
+		if p.w, p.err = l.childWalkState(w, pathName); p.err == nil {
+			p.d, p.err = l.readDirectory(pathName, patterns, p.w)
+		}
+	}()
+	return p
+}
@@ -0,0 +1,93 @@
+package dirlist
+
+import (
+	"os"
+	"strings"
+)
+
+// PathPattern is a pattern matched against the path of an entry relative to
+// the start directory instead of only its name, e.g. src/**/testdata/*.json
+// or */cmd/*.go. A "**" segment matches any number of directories.
+type PathPattern []string
+
+func splitPath(path string) []string {
+	return strings.FieldsFunc(path, func(ch rune) bool {
+		return ch == '/' || ch == os.PathSeparator
+	})
+}
+
+// IsPathPattern tells whether pattern needs to be matched as a PathPattern,
+// that is it has a "**" or a wildcard before its last path separator
+func IsPathPattern(pattern string) bool {
+	segments := splitPath(pattern)
+	for i, x := range segments {
+		if x == "**" || (i < len(segments)-1 && strings.ContainsAny(x, "*?[")) {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitPathPattern splits pattern into the directory that comes before the
+// first wildcard, which is where the search starts, and the PathPattern
+// relative to that directory
+func SplitPathPattern(pattern string) (string, PathPattern) {
+	segments := splitPath(pattern)
+	i := 0
+	for i < len(segments)-1 && !strings.ContainsAny(segments[i], "*?[") {
+		i++
+	}
+	directory := strings.Join(segments[:i], string(os.PathSeparator))
+	if strings.HasPrefix(pattern, "/") ||
+		strings.HasPrefix(pattern, string(os.PathSeparator)) {
+		directory = string(os.PathSeparator) + directory
+	}
+	return directory, PathPattern(segments[i:])
+}
+
+// matchSegments tells whether path matches p[i:], each segment being
+// matched with match()
+func (p PathPattern) matchSegments(i int, path []string,
+	match func(pattern, name string) bool) bool {
+	for ; i < len(p); i++ {
+		if p[i] == "**" {
+			for j := 0; j <= len(path); j++ {
+				if p.matchSegments(i+1, path[j:], match) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 || !match(p[i], path[0]) {
+			return false
+		}
+		path = path[1:]
+	}
+	return len(path) == 0
+}
+
+// Match tells whether the relative path segments match the pattern
+func (p PathPattern) Match(path []string,
+	match func(pattern, name string) bool) bool {
+	return p.matchSegments(0, path, match)
+}
+
+// CanMatchBelow tells whether an entry inside the directory with the
+// relative path segments could match the pattern, i.e. whether it is worth
+// descending into that directory
+func (p PathPattern) CanMatchBelow(directory []string,
+	match func(pattern, name string) bool) bool {
+	for i := 0; i < len(p); i++ {
+		if len(directory) == 0 {
+			return true
+		}
+		if p[i] == "**" {
+			return true
+		}
+		if !match(p[i], directory[0]) {
+			return false
+		}
+		directory = directory[1:]
+	}
+	return false
+}
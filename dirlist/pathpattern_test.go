@@ -0,0 +1,102 @@
+package dirlist
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func matchGlob(pattern, name string) bool {
+	matched, err := filepath.Match(pattern, name)
+	return err == nil && matched
+}
+
+func TestPathPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"**/*.go", "a.go", true},
+		{"**/*.go", "src/a.go", true},
+		{"**/*.go", "src/x/y/a.go", true},
+		{"**/*.go", "src/a.txt", false},
+		{"src/**/*.json", "src/a.json", true},
+		{"src/**/*.json", "src/x/testdata/a.json", true},
+		{"src/**/*.json", "lib/a.json", false},
+		{"src/**/testdata/*.json", "src/testdata/a.json", true},
+		{"src/**/testdata/*.json", "src/x/y/testdata/a.json", true},
+		{"src/**/testdata/*.json", "src/x/testdata/y/a.json", false},
+		{"src/**", "src", true},
+		{"src/**", "src/x/y", true},
+		{"src/**", "lib/x", false},
+		{"*/cmd/*.go", "tool/cmd/main.go", true},
+		{"*/cmd/*.go", "cmd/main.go", false},
+		{"*/cmd/*.go", "a/b/cmd/main.go", false},
+	}
+	for _, x := range tests {
+		p := PathPattern(strings.Split(x.pattern, "/"))
+		if got := p.Match(strings.Split(x.path, "/"), matchGlob); got != x.want {
+			t.Errorf("%q.Match(%q) = %v, want %v", x.pattern, x.path, got,
+				x.want)
+		}
+	}
+}
+
+func TestPathPatternCanMatchBelow(t *testing.T) {
+	tests := []struct {
+		pattern, directory string
+		want               bool
+	}{
+		{"**/*.go", "src", true},
+		{"**/*.go", "src/x/y", true},
+		{"src/**/*.json", "src", true},
+		{"src/**/*.json", "src/x/y", true},
+		{"src/**/*.json", "lib", false},
+		{"src/**/testdata/*.json", "src/x", true},
+		{"src/**", "src/x", true},
+		{"src/**", "lib", false},
+		{"*/cmd/*.go", "tool", true},
+		{"*/cmd/*.go", "tool/cmd", true},
+		{"*/cmd/*.go", "tool/lib", false},
+		// Nothing below the last segment can match
+		{"*/cmd/*.go", "tool/cmd/x", false},
+		{"src/*.go", "src", true},
+		{"src/*.go", "src/x", false},
+	}
+	for _, x := range tests {
+		p := PathPattern(strings.Split(x.pattern, "/"))
+		directory := strings.Split(x.directory, "/")
+		if got := p.CanMatchBelow(directory, matchGlob); got != x.want {
+			t.Errorf("%q.CanMatchBelow(%q) = %v, want %v", x.pattern,
+				x.directory, got, x.want)
+		}
+	}
+}
+
+func TestSplitPathPattern(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		isPattern bool
+		directory string
+		want      PathPattern
+	}{
+		{"*.go", false, "", PathPattern{"*.go"}},
+		{"src/*.go", false, "src", PathPattern{"*.go"}},
+		{"**/*.go", true, "", PathPattern{"**", "*.go"}},
+		{"src/**/*.json", true, "src", PathPattern{"**", "*.json"}},
+		{"src/lib/**", true, filepath.Join("src", "lib"), PathPattern{"**"}},
+		{"*/cmd/*.go", true, "", PathPattern{"*", "cmd", "*.go"}},
+	}
+	for _, x := range tests {
+		if got := IsPathPattern(x.pattern); got != x.isPattern {
+			t.Errorf("IsPathPattern(%q) = %v, want %v", x.pattern, got,
+				x.isPattern)
+		}
+		directory, p := SplitPathPattern(x.pattern)
+		if directory != x.directory || !reflect.DeepEqual(p, x.want) {
+			t.Errorf("SplitPathPattern(%q) = %q, %q, want %q, %q",
+				x.pattern, directory, p, x.directory, x.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package dirlist
+
+import (
+	"os"
+	"time"
+
+	"github.com/tsaost/util"
+)
+
+// Record is the machine-readable form of one listed file or directory
+type Record struct {
+	Type          string    `json:"type"`
+	Name          string    `json:"name"`
+	Path          string    `json:"path"`
+	Size          int64     `json:"size"`
+	AllocatedSize int64     `json:"allocated_size"`
+	Links         uint64    `json:"links"`
+	Mode          string    `json:"mode"`
+	ModTime       time.Time `json:"mtime"`
+	IsDir         bool      `json:"is_dir"`
+	SymlinkTarget string    `json:"symlink_target,omitempty"`
+	IsHidden      bool      `json:"hidden"`
+	IsSystem      bool      `json:"system"`
+	IsReadOnly    bool      `json:"read_only"`
+}
+
+// SummaryRecord is the final object of a /json or /ndjson listing
+type SummaryRecord struct {
+	Type             string `json:"type"`
+	FilesCount       int    `json:"files"`
+	DirectoriesCount int    `json:"directories"`
+	TotalBytes       int64  `json:"total_bytes"`
+	AllocatedBytes   int64  `json:"allocated_bytes"`
+	FreeBytes        int64  `json:"free_bytes"`
+}
+
+// NewRecord returns the Record of info, including the hidden, system
+// and read-only attributes
+func NewRecord(info util.PathInfo) Record {
+	pathName := info.PathName()
+	entry := Record{Type: "entry", Name: info.Name(), Path: pathName,
+		Size: info.Size(), AllocatedSize: allocatedSize(info),
+		Links:   linkCount(info),
+		Mode:    info.Mode().String(),
+		ModTime: info.ModTime(), IsDir: info.IsDir()}
+	if info.Mode()&os.ModeSymlink == os.ModeSymlink {
+		if link, err := util.Readlink(pathName); err == nil {
+			entry.SymlinkTarget = link
+		}
+	}
+	// Errors are ignored here, the attributes are simply reported as false
+	entry.IsHidden, _ = util.IsHiddenFile(pathName, true)
+	entry.IsSystem, _ = util.IsSystemFile(pathName)
+	entry.IsReadOnly, _ = util.IsReadOnlyFile(pathName)
+	return entry
+}
+
+// IsMachineReadable tells whether the output is meant for other programs,
+// in which case the human readable headers and summary lines are omitted
+func (o *Options) IsMachineReadable() bool {
+	return o.IsJSONFormat || o.IsNDJSONFormat || o.IsCSVFormat || o.IsTSVFormat
+}
+
+// writeRecords writes infos in the machine readable format, keeping only
+// the first NumberOfHeadLines or the last NumberOfTailLines if either is set
+func (l *Lister) writeRecords(infos []util.PathInfo) error {
+	if l.NumberOfHeadLines != 0 && l.NumberOfHeadLines < len(infos) {
+		infos = infos[:l.NumberOfHeadLines]
+	} else if l.NumberOfTailLines != 0 && l.NumberOfTailLines < len(infos) {
+		infos = infos[len(infos)-l.NumberOfTailLines:]
+	}
+	return l.writeRecordList(infos)
+}
+
+// writeRecordList writes all of infos in the machine readable format
+func (l *Lister) writeRecordList(infos []util.PathInfo) error {
+	for _, info := range infos {
+		var err error
+		if l.IsCSVFormat || l.IsTSVFormat {
+			err = l.writeCSVRecord(NewRecord(info))
+		} else {
+			err = l.writeJSONEntry(NewRecord(info))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if l.csvWriter != nil {
+		l.csvWriter.Flush()
+		return l.csvWriter.Error()
+	}
+	return nil
+}
+
+// WriteSummary ends a machine readable listing with the totals and the free
+// space of the disk. CSV and TSV have no summary, only the header is written
+// if nothing was listed.
+func (l *Lister) WriteSummary(freeBytes int64) error {
+	if l.IsCSVFormat || l.IsTSVFormat {
+		return l.flushCSV()
+	}
+	return l.writeJSONSummary(SummaryRecord{Type: "summary",
+		FilesCount:       l.Totals.FilesCount,
+		DirectoriesCount: l.Totals.DirectoriesCount,
+		TotalBytes:       l.Totals.FilesSize,
+		AllocatedBytes:   l.Totals.AllocatedSize, FreeBytes: freeBytes})
+}
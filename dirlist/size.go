@@ -0,0 +1,117 @@
+package dirlist
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SizeRange selects the files whose size is between Min and Max included,
+// the allocated size if IsAllocated is set, otherwise the size that is
+// shown first (see SpaceMode)
+type SizeRange struct {
+	Min, Max    int64
+	IsAllocated bool
+}
+
+// sizeUnits are the suffixes of the sizes, K, M, G and T are decimal like
+// on disk labels and KiB, MiB, GiB and TiB are binary
+var sizeUnits = map[string]int64{
+	"": 1, "b": 1,
+	"k": 1000, "kb": 1000, "kib": 1 << 10,
+	"m": 1000 * 1000, "mb": 1000 * 1000, "mib": 1 << 20,
+	"g": 1000 * 1000 * 1000, "gb": 1000 * 1000 * 1000, "gib": 1 << 30,
+	"t": 1000 * 1000 * 1000 * 1000, "tb": 1000 * 1000 * 1000 * 1000,
+	"tib": 1 << 40,
+}
+
+// ParseSize parses a size like 1500, 10M, 1.5G or 64KiB
+func ParseSize(text string) (int64, error) {
+	i := strings.IndexFunc(text, func(ch rune) bool {
+		return (ch < '0' || ch > '9') && ch != '.'
+	})
+	if i < 0 {
+		i = len(text)
+	}
+	unit, ok := sizeUnits[strings.ToLower(text[i:])]
+	if !ok || i == 0 {
+		return 0, fmt.Errorf("Bad size \"%s\"", text)
+	}
+	value, err := strconv.ParseFloat(text[:i], 64)
+	if err != nil || value*float64(unit) > math.MaxInt64 {
+		return 0, fmt.Errorf("Bad size \"%s\"", text)
+	}
+	return int64(value * float64(unit)), nil
+}
+
+// ParseSizeRange parses a size filter: >N, >=N, <N, <=N, =N or N, a range
+// N-M (both included), N- for at least N, -M for at most M or "empty" for
+// the files of size 0
+func ParseSizeRange(spec string) (SizeRange, error) {
+	r := SizeRange{Max: math.MaxInt64}
+	if strings.EqualFold(spec, "empty") {
+		r.Max = 0
+		return r, nil
+	}
+	for _, x := range []string{">=", "<=", ">", "<", "="} {
+		if !strings.HasPrefix(spec, x) {
+			continue
+		}
+		size, err := ParseSize(spec[len(x):])
+		if err != nil {
+			return r, err
+		}
+		switch x {
+		case ">=":
+			r.Min = size
+		case "<=":
+			r.Max = size
+		case ">":
+			r.Min = size + 1
+		case "<":
+			r.Max = size - 1
+		default:
+			r.Min, r.Max = size, size
+		}
+		return r, nil
+	}
+	if i := strings.IndexByte(spec, '-'); i >= 0 {
+		if spec == "-" {
+			return r, fmt.Errorf("Bad size range \"%s\"", spec)
+		}
+		var err error
+		if i > 0 {
+			if r.Min, err = ParseSize(spec[:i]); err != nil {
+				return r, err
+			}
+		}
+		if i < len(spec)-1 {
+			if r.Max, err = ParseSize(spec[i+1:]); err != nil {
+				return r, err
+			}
+		}
+		if r.Min > r.Max {
+			return r, fmt.Errorf("Bad size range \"%s\"", spec)
+		}
+		return r, nil
+	}
+	size, err := ParseSize(spec)
+	r.Min, r.Max = size, size
+	return r, err
+}
+
+// isSizeSelected tells whether the file info passes all SizeRanges
+func (o *Options) isSizeSelected(info os.FileInfo) bool {
+	for _, x := range o.SizeRanges {
+		size := o.entrySize(info)
+		if x.IsAllocated {
+			size = allocatedSize(info)
+		}
+		if size < x.Min || size > x.Max {
+			return false
+		}
+	}
+	return true
+}
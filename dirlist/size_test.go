@@ -0,0 +1,74 @@
+package dirlist
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		text string
+		want int64
+		ok   bool
+	}{
+		{"1500", 1500, true},
+		{"0", 0, true},
+		{"10K", 10000, true},
+		{"10k", 10000, true},
+		{"10kB", 10000, true},
+		{"64KiB", 64 << 10, true},
+		{"1.5G", 1500000000, true},
+		{"1.5GiB", 3 << 29, true},
+		{"2T", 2000000000000, true},
+		{"100B", 100, true},
+		{"", 0, false},
+		{"M", 0, false},
+		{"10X", 0, false},
+		{"10 M", 0, false},
+		{"10MiBs", 0, false},
+		{"1.2.3K", 0, false},
+		{"99999999T", 0, false},
+	}
+	for _, x := range tests {
+		got, err := ParseSize(x.text)
+		if (err == nil) != x.ok || (x.ok && got != x.want) {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", x.text, got, err,
+				x.want)
+		}
+	}
+}
+
+func TestParseSizeRange(t *testing.T) {
+	const max = math.MaxInt64
+	tests := []struct {
+		spec     string
+		min, max int64
+		ok       bool
+	}{
+		{">10M", 10000001, max, true},
+		{">=10M", 10000000, max, true},
+		{"<1KiB", 0, 1023, true},
+		{"<=1KiB", 0, 1024, true},
+		{"=100", 100, 100, true},
+		{"100", 100, 100, true},
+		{"empty", 0, 0, true},
+		{"EMPTY", 0, 0, true},
+		{"1K-2K", 1000, 2000, true},
+		{"1K-", 1000, max, true},
+		{"-2K", 0, 2000, true},
+		{"2K-1K", 0, 0, false},
+		{"-", 0, 0, false},
+		{"1X-2K", 0, 0, false},
+		{"1K-2X", 0, 0, false},
+		{">", 0, 0, false},
+		{">=10Q", 0, 0, false},
+	}
+	for _, x := range tests {
+		r, err := ParseSizeRange(x.spec)
+		if (err == nil) != x.ok ||
+			(x.ok && (r.Min != x.min || r.Max != x.max)) {
+			t.Errorf("ParseSizeRange(%q) = %+v, %v, want %d-%d", x.spec, r,
+				err, x.min, x.max)
+		}
+	}
+}
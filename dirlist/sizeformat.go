@@ -0,0 +1,136 @@
+package dirlist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tsaost/util/format"
+)
+
+// The size formats of SizeFormat, the default being the digits grouped by
+// commas like dir does
+const (
+	SizeFormatRaw     = "raw"     // 1234567
+	SizeFormatGrouped = "grouped" // 1,234,567 or 1.234.567 depending on Locale
+	SizeFormatIEC     = "iec"     // 1.2 MiB
+	SizeFormatSI      = "si"      // 1.2 MB
+)
+
+// ParseSizeFormat checks the /sf: size format
+func ParseSizeFormat(spec string) (string, error) {
+	switch spec {
+	case SizeFormatRaw, SizeFormatGrouped, SizeFormatIEC, SizeFormatSI:
+		return spec, nil
+	}
+	return "", fmt.Errorf("Bad size format \"%s\", must be one of "+
+		"raw, grouped, iec or si", spec)
+}
+
+// ParseSizeUnit parses the unit of /unit:, one of the suffixes of
+// ParseSize() like K, M, MiB or GiB
+func ParseSizeUnit(spec string) (string, error) {
+	if size, ok := sizeUnits[strings.ToLower(spec)]; ok && size > 1 {
+		return spec, nil
+	}
+	return "", fmt.Errorf("Bad size unit \"%s\", must be one of "+
+		"K, M, G, T, KiB, MiB, GiB or TiB", spec)
+}
+
+// numberSeparators returns the digit group and decimal separators of the
+// language of locale
+func numberSeparators(locale string) (group, decimal string) {
+	switch localeLanguage(locale) {
+	case "de", "es", "it", "nl", "pt", "da", "tr", "id", "el":
+		if strings.Contains(strings.ToLower(locale), "_ch") {
+			return "'", "."
+		}
+		return ".", ","
+	case "fr", "sv", "fi", "nb", "nn", "no", "ru", "pl", "cs", "sk", "uk",
+		"hu":
+		return " ", ","
+	}
+	return ",", "."
+}
+
+// groupDigits separates the groups of three digits of n with separator
+func groupDigits(n int64, separator string) string {
+	digits := strconv.FormatInt(n, 10)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for i, x := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteRune(x)
+	}
+	return sign + b.String()
+}
+
+// IsSizeInBytes tells whether FormatSize() gives a number of bytes, to be
+// followed by "bytes", rather than a size with its unit
+func (o *Options) IsSizeInBytes() bool {
+	return o.SizeUnit == "" &&
+		o.SizeFormat != SizeFormatIEC && o.SizeFormat != SizeFormatSI
+}
+
+// FormatSize formats size according to SizeFormat, SizeUnit and Locale
+func (o *Options) FormatSize(size int64) string {
+	group, decimal := numberSeparators(o.Locale)
+	if o.SizeUnit != "" {
+		// Rounded up like du does, so that a small file is not 0
+		unit := sizeUnits[strings.ToLower(o.SizeUnit)]
+		return groupDigits((size+unit-1)/unit, group) + o.SizeUnit
+	}
+	switch {
+	case o.SizeFormat == SizeFormatRaw ||
+		(o.SizeFormat == "" && o.IsNoCommaSeparator):
+		return strconv.FormatInt(size, 10)
+	case o.SizeFormat == SizeFormatGrouped:
+		return groupDigits(size, group)
+	case o.SizeFormat == SizeFormatIEC:
+		return humanReadableSize(size, 1024,
+			[]string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}, decimal)
+	case o.SizeFormat == SizeFormatSI:
+		return humanReadableSize(size, 1000,
+			[]string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}, decimal)
+	}
+	return format.CommaSeparated(size)
+}
+
+// humanReadableSize shows size in the largest unit in which it is at least
+// 1, with one decimal below 10 like ls -h
+func humanReadableSize(size, base int64, units []string,
+	decimal string) string {
+	if size < base && size > -base {
+		return strconv.FormatInt(size, 10) + " " + units[0]
+	}
+	value, i := float64(size), 0
+	for (value >= float64(base) || value <= -float64(base)) &&
+		i < len(units)-1 {
+		value /= float64(base)
+		i++
+	}
+	// The unit and the decimal are checked again once rounded, 1023.99 KiB
+	// is shown as 1.0 MiB and 9.99 KiB as 10 KiB
+	decimals := 0
+	if value < 10 && value > -10 {
+		decimals = 1
+	}
+	rounded, _ := strconv.ParseFloat(
+		strconv.FormatFloat(value, 'f', decimals, 64), 64)
+	if (rounded >= float64(base) || rounded <= -float64(base)) &&
+		i < len(units)-1 {
+		value /= float64(base)
+		i++
+		decimals = 1
+	} else if rounded >= 10 || rounded <= -10 {
+		decimals = 0
+	}
+	text := strings.Replace(strconv.FormatFloat(value, 'f', decimals, 64),
+		".", decimal, 1)
+	return text + " " + units[i]
+}
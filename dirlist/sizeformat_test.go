@@ -0,0 +1,34 @@
+package dirlist
+
+import (
+	"testing"
+)
+
+func TestFormatSizeHumanReadable(t *testing.T) {
+	tests := []struct {
+		format string
+		size   int64
+		want   string
+	}{
+		{SizeFormatIEC, 0, "0 B"},
+		{SizeFormatIEC, 1023, "1023 B"},
+		{SizeFormatIEC, 1024, "1.0 KiB"},
+		{SizeFormatIEC, 1536, "1.5 KiB"},
+		{SizeFormatIEC, 10239, "10 KiB"},
+		{SizeFormatIEC, 10240, "10 KiB"},
+		{SizeFormatIEC, 1048575, "1.0 MiB"},
+		{SizeFormatIEC, 1048576, "1.0 MiB"},
+		{SizeFormatIEC, -1048575, "-1.0 MiB"},
+		{SizeFormatSI, 999, "999 B"},
+		{SizeFormatSI, 999999, "1.0 MB"},
+		{SizeFormatSI, 9999, "10 kB"},
+		{SizeFormatSI, 123456789, "123 MB"},
+	}
+	for _, x := range tests {
+		o := &Options{SizeFormat: x.format}
+		if got := o.FormatSize(x.size); got != x.want {
+			t.Errorf("FormatSize(%d) with %s = %q, want %q", x.size,
+				x.format, got, x.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package dirlist
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/tsaost/util"
+)
+
+// SortKey is one key of a sort specification
+type SortKey struct {
+	// 'n'ame, 'e'xtension, 's'ize, 'a'llocated size, 'd'ate or 'g'roup
+	// directories first
+	Key      byte
+	Reversed bool
+	Natural  bool // compare numbers in names and extensions by value
+}
+
+// DefaultSortKeys lists directories first, then sorts by name
+var DefaultSortKeys = []SortKey{{Key: 'g'}, {Key: 'n'}}
+
+// ParseSortSpec parses a sort specification like the one of "dir /o:gn-d",
+// that is any sequence of the n, e, s, a, d and g keys, each optionally
+// preceded by '-' to reverse its order. N and E are the natural order
+// versions of n and e.
+func ParseSortSpec(spec string) ([]SortKey, error) {
+	keys := []SortKey{}
+	reversed := false
+	for i := 0; i < len(spec); i++ {
+		ch := spec[i]
+		switch ch {
+		case '-':
+			if reversed {
+				return nil, fmt.Errorf("Bad sort order \"%s\"", spec)
+			}
+			reversed = true
+			continue
+		case 'n', 'e', 's', 'a', 'd', 'g':
+			keys = append(keys, SortKey{Key: ch, Reversed: reversed})
+			reversed = false
+		case 'N', 'E':
+			keys = append(keys, SortKey{Key: ch - 'A' + 'a',
+				Reversed: reversed, Natural: true})
+			reversed = false
+		default:
+			return nil, fmt.Errorf("Bad sort key '%c' in \"%s\", "+
+				"must be one of n, N, e, E, s, a, d or g", ch, spec)
+		}
+	}
+	if reversed || len(keys) == 0 {
+		return nil, fmt.Errorf("Bad sort order \"%s\"", spec)
+	}
+	return keys, nil
+}
+
+// compareBy returns -1, 0 or 1 depending on whether a comes before, is equal
+// to or comes after b for key (ignoring key.Reversed)
+func compareBy(key SortKey, a, b util.PathInfo, c collator) int {
+	switch key.Key {
+	case 'n':
+		return c.compare(a.Name(), b.Name(), key.Natural)
+	case 'e':
+		return c.compare(filepath.Ext(a.Name()), filepath.Ext(b.Name()),
+			key.Natural)
+	case 's':
+		return compareInt64(a.Size(), b.Size())
+	case 'a':
+		return compareInt64(allocatedSize(a), allocatedSize(b))
+	case 'd':
+		return compareTime(entryTime(a), entryTime(b))
+	case 'g':
+		aIsDir, bIsDir := a.IsDir(), b.IsDir()
+		if aIsDir && !bIsDir {
+			return -1
+		}
+		if !aIsDir && bIsDir {
+			return 1
+		}
+	}
+	return 0
+}
+
+func compareInt64(a, b int64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+func compareTime(a, b time.Time) int {
+	if a.Before(b) {
+		return -1
+	}
+	if a.After(b) {
+		return 1
+	}
+	return 0
+}
+
+// byKeys sorts by each key in turn, the next key is only used to break a tie
+type byKeys struct {
+	infos    []util.PathInfo
+	keys     []SortKey
+	collator collator
+}
+
+func (f byKeys) Len() int      { return len(f.infos) }
+func (f byKeys) Swap(i, j int) { f.infos[i], f.infos[j] = f.infos[j], f.infos[i] }
+func (f byKeys) Less(i, j int) bool {
+	for _, x := range f.keys {
+		c := compareBy(x, f.infos[i], f.infos[j], f.collator)
+		if x.Reversed {
+			c = -c
+		}
+		if c != 0 {
+			return c < 0
+		}
+	}
+	return false
+}
+
+// sortInfos sorts infos according to SortKeys (DefaultSortKeys if nil),
+// comparing the names with the collation rules of Locale.
+// Ties are broken by name (in natural order if any key is natural) unless
+// the name is already a key, and a stable sort is used so entries with the
+// same name keep the Readdir order. Nothing is done with IsUnsorted. The
+// 's' key sorts on the allocated size with SpaceAllocated.
+func (o *Options) sortInfos(infos []util.PathInfo) {
+	if o.IsUnsorted {
+		return
+	}
+	keys := o.SortKeys
+	if keys == nil {
+		keys = DefaultSortKeys
+	}
+	if o.SpaceMode == SpaceAllocated {
+		// The size is the allocated size
+		keys = append([]SortKey{}, keys...)
+		for i, x := range keys {
+			if x.Key == 's' {
+				keys[i].Key = 'a'
+			}
+		}
+	}
+	hasName, natural := false, false
+	for _, x := range keys {
+		if x.Key == 'n' {
+			hasName = true
+		}
+		natural = natural || x.Natural
+	}
+	if !hasName {
+		keys = append(keys[:len(keys):len(keys)],
+			SortKey{Key: 'n', Natural: natural})
+	}
+	sort.Stable(byKeys{infos, keys, newCollator(o.Locale)})
+}
@@ -0,0 +1,38 @@
+package dirlist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSortSpec(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []SortKey
+	}{
+		{"n", []SortKey{{Key: 'n'}}},
+		{"-d", []SortKey{{Key: 'd', Reversed: true}}},
+		{"gn-d", []SortKey{{Key: 'g'}, {Key: 'n'},
+			{Key: 'd', Reversed: true}}},
+		{"-sa", []SortKey{{Key: 's', Reversed: true}, {Key: 'a'}}},
+		{"N-E", []SortKey{{Key: 'n', Natural: true},
+			{Key: 'e', Reversed: true, Natural: true}}},
+		{"", nil},
+		{"-", nil},
+		{"n-", nil},
+		{"--n", nil},
+		{"x", nil},
+		{"nD", nil},
+	}
+	for _, x := range tests {
+		got, err := ParseSortSpec(x.spec)
+		if x.want == nil {
+			if err == nil {
+				t.Errorf("ParseSortSpec(%q) = %v, want an error", x.spec, got)
+			}
+		} else if err != nil || !reflect.DeepEqual(got, x.want) {
+			t.Errorf("ParseSortSpec(%q) = %v, %v, want %v", x.spec, got,
+				err, x.want)
+		}
+	}
+}
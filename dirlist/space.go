@@ -0,0 +1,58 @@
+package dirlist
+
+import (
+	"fmt"
+	"os"
+)
+
+// The values of SpaceMode, which size is shown, totalled, sorted on and
+// filtered on: the apparent size (the length of the file), the allocated
+// size (the space it uses on disk, less for sparse files and more for
+// small files on file systems with large blocks) or both
+const (
+	SpaceApparent  = "apparent"
+	SpaceAllocated = "alloc"
+	SpaceBoth      = "both"
+)
+
+// ParseSpaceMode checks the /space: mode
+func ParseSpaceMode(spec string) (string, error) {
+	switch spec {
+	case SpaceApparent, SpaceAllocated, SpaceBoth:
+		return spec, nil
+	}
+	return "", fmt.Errorf("Bad space \"%s\", must be one of "+
+		"apparent, alloc or both", spec)
+}
+
+// allocatedSize returns the space info uses on disk, or its apparent size
+// if that is not known
+func allocatedSize(info os.FileInfo) int64 {
+	switch x := info.(type) {
+	case *compactInfo:
+		return x.allocated
+	case *extendedInfo:
+		if x.directory != nil {
+			return x.directory.AllocatedSize
+		}
+	}
+	if size, ok := getAllocatedSize(info); ok {
+		return size
+	}
+	return info.Size()
+}
+
+// isShowingAllocated tells whether the allocated sizes are shown
+func (o *Options) isShowingAllocated() bool {
+	return o.SpaceMode == SpaceAllocated || o.SpaceMode == SpaceBoth
+}
+
+// entrySize returns the size of info that is shown first, sorted on with
+// the 's' key and filtered on: the allocated size with SpaceAllocated,
+// otherwise the apparent size
+func (o *Options) entrySize(info os.FileInfo) int64 {
+	if o.SpaceMode == SpaceAllocated {
+		return allocatedSize(info)
+	}
+	return info.Size()
+}
@@ -0,0 +1,93 @@
+package dirlist
+
+import (
+	"fmt"
+
+	"github.com/tsaost/util"
+)
+
+// isStreamed tells whether the entries are printed as they are read instead
+// of once the whole directory has been read, which is only possible when
+// they are not sorted and their layout doesn't depend on all of them
+func (l *Lister) isStreamed() bool {
+	return l.IsUnsorted && !l.IsFlatListing && !l.IsWideDisplayFormat
+}
+
+// entryLimiter applies NumberOfHeadLines and NumberOfTailLines to entries
+// that arrive in batches, only the last ones are kept for the tail
+type entryLimiter struct {
+	count int
+	tail  []util.PathInfo
+}
+
+// streamDirectory prints the entries of directory as they are read and
+// then its summary line
+func (l *Lister) streamDirectory(directory string, patterns []string,
+	w walkState) (*Directory, error) {
+	d := &Directory{Path: directory, LinkTarget: w.linkTarget}
+	limiter := &entryLimiter{}
+	err := l.readEntries(d, patterns, w, func(infos []util.PathInfo) error {
+		l.printWarnings(d)
+		return l.streamEntries(limiter, infos)
+	})
+	l.printWarnings(d)
+	if err != nil {
+		return nil, err
+	}
+
+	isOmitted := false
+	if l.NumberOfHeadLines != 0 {
+		isOmitted = limiter.count > l.NumberOfHeadLines
+	} else if l.NumberOfTailLines != 0 {
+		isOmitted = limiter.count > l.NumberOfTailLines
+	}
+	l.countLinks(d)
+	if l.IsMachineReadable() {
+		return d, l.writeRecordList(limiter.tail)
+	}
+	if isOmitted {
+		fmt.Fprintln(l.Out, omittedLine)
+	}
+	if err = l.printEntries(limiter.tail); err != nil {
+		return nil, err
+	}
+	l.printDirectorySummary(d)
+	return d, nil
+}
+
+// streamEntries prints infos unless they are past NumberOfHeadLines, or
+// keeps the last NumberOfTailLines of them to be printed at the end
+func (l *Lister) streamEntries(limiter *entryLimiter,
+	infos []util.PathInfo) error {
+	start := limiter.count
+	limiter.count += len(infos)
+	if l.NumberOfHeadLines != 0 {
+		if start >= l.NumberOfHeadLines {
+			return nil
+		}
+		if limiter.count > l.NumberOfHeadLines {
+			infos = infos[:l.NumberOfHeadLines-start]
+		}
+	} else if l.NumberOfTailLines != 0 {
+		for _, x := range infos {
+			limiter.tail = append(limiter.tail, l.retainedInfo(x))
+		}
+		if extra := len(limiter.tail) - l.NumberOfTailLines; extra > 0 {
+			limiter.tail = append(limiter.tail[:0], limiter.tail[extra:]...)
+		}
+		return nil
+	}
+	return l.printEntries(infos)
+}
+
+// printEntries prints infos without any summary, the Windows listing uses
+// the default size width since the largest size is not known yet
+func (l *Lister) printEntries(infos []util.PathInfo) error {
+	if l.IsMachineReadable() {
+		return l.writeRecordList(infos)
+	}
+	for _, line := range l.Lines(&Directory{Infos: infos}) {
+		fmt.Fprintln(l.Out, line)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package dirlist
+
+import (
+	"fmt"
+	"time"
+)
+
+// The named time formats of TimeFormat, anything else is a Go layout
+const (
+	TimeFormat24Hour      = "24h"      // 2024-01-31  13:45
+	TimeFormatISO         = "iso"      // 2024-01-31T13:45:07+01:00
+	TimeFormatISONano     = "iso-ns"   // with the nanoseconds
+	TimeFormatRelative    = "relative" // 3h ago
+	windowsTimeLayout     = "2006-01-02  03:04 PM"
+	unixRecentTimeLayout  = "Jan _2 15:04"
+	unixOldTimeLayout     = "Jan _2  2006"
+	unixRecentTimeHorizon = 6 * 30 * 24 * time.Hour // like ls
+)
+
+var timeFormatLayouts = map[string]string{
+	TimeFormat24Hour:  "2006-01-02  15:04",
+	TimeFormatISO:     "2006-01-02T15:04:05Z07:00",
+	TimeFormatISONano: "2006-01-02T15:04:05.000000000Z07:00",
+}
+
+// ParseTimeFormat checks the /tf: time format, one of the named formats or
+// a Go layout like "02/01/2006 15:04"
+func ParseTimeFormat(spec string) (string, error) {
+	if _, ok := timeFormatLayouts[spec]; ok || spec == TimeFormatRelative {
+		return spec, nil
+	}
+	if spec == "" || time.Unix(0, 0).Format(spec) == spec {
+		return "", fmt.Errorf("Bad time format \"%s\", must be one of "+
+			"24h, iso, iso-ns, relative or a Go layout like \"%s\"",
+			spec, "2006-01-02 15:04:05")
+	}
+	return spec, nil
+}
+
+// formatTime formats t according to TimeFormat and Location, unixStyle
+// selects the default format of the ls -l listing instead of the one of dir
+func (l *Lister) formatTime(t time.Time, unixStyle bool) string {
+	if l.Location != nil {
+		t = t.In(l.Location)
+	} else {
+		t = t.Local()
+	}
+	if layout, ok := timeFormatLayouts[l.TimeFormat]; ok {
+		return t.Format(layout)
+	}
+	switch {
+	case l.TimeFormat == TimeFormatRelative:
+		return fmt.Sprintf("%8s", relativeTime(t, time.Now()))
+	case l.TimeFormat != "":
+		return t.Format(l.TimeFormat)
+	case !unixStyle:
+		return t.Format(windowsTimeLayout)
+	}
+	if age := time.Since(t); age < unixRecentTimeHorizon && age > -time.Hour {
+		return t.Format(unixRecentTimeLayout)
+	}
+	return t.Format(unixOldTimeLayout)
+}
+
+// relativeTime returns how long before now t is in the largest unit, like
+// "3h ago" or "2w ago"
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	suffix := " ago"
+	if d < 0 {
+		d, suffix = -d, " ahead"
+	}
+	units := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"y", 365 * 24 * time.Hour}, {"mo", 30 * 24 * time.Hour},
+		{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour},
+		{"h", time.Hour}, {"m", time.Minute},
+	}
+	for _, x := range units {
+		if d >= x.duration {
+			return fmt.Sprintf("%d%s%s", d/x.duration, x.name, suffix)
+		}
+	}
+	return "just now"
+}
@@ -0,0 +1,180 @@
+package dirlist
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tsaost/util"
+)
+
+// treeNode is a directory of the disk usage tree with the total of the
+// files below it. Children is only kept down to TreeDepth.
+type treeNode struct {
+	name     string
+	total    Summary
+	children []*treeNode
+}
+
+// ListTree prints the disk usage tree of directory, like du -d TreeDepth
+// sorted by size: one line per directory with the total size of the files
+// below it and its percentage of the parent. The files are filtered as in
+// the listing (hidden, read-only, excludes and patterns) and each file with
+// several links is counted once, also across the trees of several calls,
+// and the totals are added to Totals. The directories smaller than
+// TreeMinSize or TreeMinPercent of their parent are grouped in one line.
+func (l *Lister) ListTree(directory string, patterns []string) error {
+	defer l.warnTimeFallback()
+	w := l.newWalkState(directory)
+	w.isSizing = true
+	root, err := l.readTree(directory, patterns, w, 0)
+	if err != nil {
+		return err
+	}
+	root.name = directory
+	l.Totals.FilesCount += root.total.FilesCount
+	l.Totals.DirectoriesCount += root.total.DirectoriesCount
+	l.Totals.FilesSize += root.total.FilesSize
+	l.Totals.AllocatedSize += root.total.AllocatedSize
+
+	width := len(l.FormatSize(l.treeSize(root.total)))
+	if n := len(l.FormatSize(root.total.AllocatedSize)); n > width &&
+		l.SpaceMode == SpaceBoth {
+		width = n
+	}
+	fmt.Fprintln(l.Out)
+	l.printTreeNode(root, l.treeSize(root.total), width, "", "")
+	fmt.Fprintln(l.Out)
+	return nil
+}
+
+// ListTrees prints the disk usage tree of each directory of an explicit
+// list of absolute path names, everything in them except the hidden files
+// being counted like with ListPaths()
+func (l *Lister) ListTrees(pathList []string) error {
+	l.listAllFiles()
+	for _, x := range pathList {
+		if err := l.ListTree(x, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTree reads directory and everything below it at depth (0 for the
+// start directory). The directories that can't be read below the start
+// one are reported and skipped.
+func (l *Lister) readTree(directory string, patterns []string, w walkState,
+	depth int) (*treeNode, error) {
+	node := &treeNode{name: filepath.Base(directory)}
+	d := &Directory{Path: directory}
+	err := l.readEntries(d, patterns, w, func([]util.PathInfo) error {
+		return nil
+	})
+	l.printWarnings(d)
+	if err != nil {
+		return node, err
+	}
+	l.countLinks(d)
+	node.total = d.Summary
+	for _, x := range d.SubDirectories {
+		child, err := l.childWalkState(w, x)
+		if err != nil {
+			continue
+		}
+		c, err := l.readTree(x, patterns, child, depth+1)
+		if err != nil {
+			fmt.Fprintln(l.messageOut(), err)
+		}
+		node.total.FilesCount += c.total.FilesCount
+		node.total.DirectoriesCount += c.total.DirectoriesCount
+		node.total.FilesSize += c.total.FilesSize
+		node.total.AllocatedSize += c.total.AllocatedSize
+		if l.TreeDepth == 0 || depth < l.TreeDepth {
+			node.children = append(node.children, c)
+		}
+	}
+	return node, nil
+}
+
+// treeSize returns the size of s that the tree shows, sorts on and
+// compares to the thresholds
+func (o *Options) treeSize(s Summary) int64 {
+	if o.SpaceMode == SpaceAllocated {
+		return s.AllocatedSize
+	}
+	return s.FilesSize
+}
+
+// isBelowTreeThreshold tells whether a directory of size is hidden in a
+// parent of parentSize
+func (o *Options) isBelowTreeThreshold(size, parentSize int64) bool {
+	if size < o.TreeMinSize {
+		return true
+	}
+	return o.TreeMinPercent > 0 &&
+		float64(size) < o.TreeMinPercent*float64(parentSize)/100
+}
+
+func (l *Lister) printTreeNode(node *treeNode, parentSize int64, width int,
+	prefix, childPrefix string) {
+	size := l.treeSize(node.total)
+	l.printTreeLine(node.total, parentSize, width, prefix+node.name)
+
+	c := newCollator(l.Locale)
+	sort.SliceStable(node.children, func(i, j int) bool {
+		a, b := node.children[i], node.children[j]
+		if x, y := l.treeSize(a.total), l.treeSize(b.total); x != y {
+			return x > y
+		}
+		return c.compare(a.name, b.name, false) < 0
+	})
+	var shown []*treeNode
+	hidden := Summary{}
+	hiddenCount := 0
+	for _, x := range node.children {
+		if l.isBelowTreeThreshold(l.treeSize(x.total), size) {
+			hiddenCount++
+			hidden.FilesSize += x.total.FilesSize
+			hidden.AllocatedSize += x.total.AllocatedSize
+		} else {
+			shown = append(shown, x)
+		}
+	}
+	for i, x := range shown {
+		if i == len(shown)-1 && hiddenCount == 0 {
+			l.printTreeNode(x, size, width, childPrefix+"└── ",
+				childPrefix+"    ")
+		} else {
+			l.printTreeNode(x, size, width, childPrefix+"├── ",
+				childPrefix+"│   ")
+		}
+	}
+	if hiddenCount > 0 {
+		name := "1 smaller directory"
+		if hiddenCount > 1 {
+			name = fmt.Sprintf("%d smaller directories", hiddenCount)
+		}
+		l.printTreeLine(hidden, size, width,
+			childPrefix+"└── ["+name+"]")
+	}
+}
+
+// printTreeLine prints the size of s (both sizes with SpaceBoth), its
+// percentage of parentSize and name
+func (l *Lister) printTreeLine(s Summary, parentSize int64, width int,
+	name string) {
+	size := l.treeSize(s)
+	percent := "     -"
+	if parentSize > 0 {
+		percent = fmt.Sprintf("%5.1f%%", float64(size)*100/float64(parentSize))
+	}
+	var line strings.Builder
+	fmt.Fprintf(&line, "%*s", width, l.FormatSize(size))
+	if l.SpaceMode == SpaceBoth {
+		fmt.Fprintf(&line, " %*s", width, l.FormatSize(s.AllocatedSize))
+	}
+	fmt.Fprintf(&line, " %s  %s", percent, name)
+	fmt.Fprintln(l.Out, line.String())
+}
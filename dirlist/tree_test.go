@@ -0,0 +1,63 @@
+package dirlist
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListTrees(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]int{
+		"a.txt": 100, "sub/b.bin": 2000, "sub/deep/c": 30,
+		"other/d.txt": 400, "sub/.hid/e": 5000,
+	}
+	for name, size := range files {
+		pathName := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(pathName), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(pathName, make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	options := NewOptions()
+	options.IsDiskUsageTree = true
+	options.SizeFormat = SizeFormatRaw
+	var out bytes.Buffer
+	l := NewLister(options, &out)
+	if err := l.ListTrees([]string{filepath.Join(root, "sub"),
+		filepath.Join(root, "other")}); err != nil {
+		t.Fatal(err)
+	}
+	if l.Totals.FilesCount != 3 || l.Totals.FilesSize != 2430 {
+		t.Errorf("Totals = %+v, want 3 files of 2430 bytes\n%s", l.Totals,
+			out.String())
+	}
+	for _, x := range []string{"2030", "400", "30"} {
+		if !strings.Contains(out.String(), x+" ") {
+			t.Errorf("%s missing from the tree\n%s", x, out.String())
+		}
+	}
+	if strings.Contains(out.String(), ".hid") {
+		t.Errorf("hidden directory in the tree\n%s", out.String())
+	}
+
+	// /tree /a-h leaves the hidden directories out too
+	options = NewOptions()
+	options.IsDiskUsageTree = true
+	options.IsExcludeHiddenFiles = true
+	out.Reset()
+	l = NewLister(options, &out)
+	err := l.ListTree(filepath.Join(root, "sub"), []string{"*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Totals.FilesCount != 2 || l.Totals.FilesSize != 2030 {
+		t.Errorf("Totals = %+v, want 2 files of 2030 bytes\n%s", l.Totals,
+			out.String())
+	}
+}
@@ -18,519 +18,252 @@ import (
 	"log"
 	"os"
 	"runtime"
-	"sort"
 	"strings"
 	"strconv"
 	"time"
 	"path/filepath"
 	"github.com/tsaost/util"
 	"github.com/tsaost/util/cmd"
-	"github.com/tsaost/util/format"
+	"github.com/tsaost/xutility/dirlist"
 )
 
-var isSortByTime, isSortByTimeReversed bool
-var isSortBySize, isSortBySizeReversed bool
-var isSortByName, isSortByNameReversed bool
-var isSortByExtension, isSortByExtensionReversed bool
-
-var isShowHiddenFilesOnly, isExcludeHiddenFiles, isHiddenOptionExplicit bool
-var isShowReadOnlyFilesOnly, isExcludeReadOnlyFiles bool
-var isShowDirectoryOnly, isExcludeDirectory bool
-var isRecurseSubDirectory, isNoCommaSeparator bool
-var isShowFullPath, isShowPartialPath bool
-var isBareDisplayFormat, isWideDisplayFormat, isMatchAllFiles bool
-var isShowVolumeInformation, isUnixStyleListing, isShowNumericUnixFileMode bool
-var isShowQuoteForFileWithSpaces bool
-var numberOfHeadLines, numberOfTailLines int
+var isHiddenOptionExplicit, isShowVolumeInformation bool
 
 var isWindows = runtime.GOOS == "windows"
 var isUnix = !isWindows
-var isIgnoreFilenameCase bool
-var isSortByDirThenName = true
-var wideFormatLineWidth = 80
-var fileCutoffTime = time.Time{}
-
-type byDirectoryThenName []util.PathInfo
-func (f byDirectoryThenName) Len() int           { return len(f) }
-func (f byDirectoryThenName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f byDirectoryThenName) Less(i, j int) bool {
-	iIsDir := f[i].IsDir()
-	jIsDir := f[j].IsDir()
-	if iIsDir && !jIsDir {
-		return true
-	}
-	if !iIsDir && jIsDir {
-		return false
-	}
-	return strings.ToLower(f[i].Name()) < strings.ToLower(f[j].Name())
-}
-
-type byName []util.PathInfo
-func (f byName) Len() int           { return len(f) }
-func (f byName) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f byName) Less(i, j int) bool {
-	return strings.ToLower(f[i].Name()) < strings.ToLower(f[j].Name())
-}
-
-type byNameReversed []util.PathInfo
-func (f byNameReversed) Len() int           { return len(f) }
-func (f byNameReversed) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f byNameReversed) Less(i, j int) bool {
-	return strings.ToLower(f[i].Name()) > strings.ToLower(f[j].Name())
-}
-	
-type byExtension []util.PathInfo
-func (f byExtension) Len() int           { return len(f) }
-func (f byExtension) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f byExtension) Less(i, j int) bool {
-	return strings.ToLower(filepath.Ext(f[i].Name())) <
-		strings.ToLower(filepath.Ext(f[j].Name()))
-}
-
-type byExtensionReversed []util.PathInfo
-func (f byExtensionReversed) Len() int           { return len(f) }
-func (f byExtensionReversed) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f byExtensionReversed) Less(i, j int) bool {
-	return strings.ToLower(filepath.Ext(f[i].Name())) >
-		strings.ToLower(filepath.Ext(f[j].Name()))
-}
-	
-type byTime []util.PathInfo
-func (f byTime) Len() int { return len(f) }
-func (f byTime) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f byTime) Less(i, j int) bool { 
-	return f[i].ModTime().Before(f[j].ModTime())
-}
-
-type byTimeReversed []util.PathInfo
-func (f byTimeReversed) Len() int           { return len(f) }
-func (f byTimeReversed) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f byTimeReversed) Less(i, j int) bool {
-	return f[i].ModTime().After(f[j].ModTime())
-}
-
-type bySize []util.PathInfo
-func (f bySize) Len() int           { return len(f) }
-func (f bySize) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f bySize) Less(i, j int) bool { return f[i].Size() < f[j].Size() }
-
-type bySizeReversed []util.PathInfo
-func (f bySizeReversed) Len() int           { return len(f) }
-func (f bySizeReversed) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
-func (f bySizeReversed) Less(i, j int) bool { return f[i].Name() > f[j].Name() }
 
-var totalFilesCount, totalDirectoriesCount int
-var totalFilesSize int64
+var options = dirlist.NewOptions()
 
-func getWideFormatFileListing(infos []util.PathInfo) []string {
-	listing := make([]string, 0, len(infos))
+var isOptionMustStartWithMinus bool
 
-    maxLen := 13
-    for _, x := range infos {
-		name := x.Name()
-		lenName := len(name)
-		if x.IsDir() {
-			lenName += 2 // Need to put [..] around directory 
-		}
-		if lenName > maxLen {
-			maxLen = lenName
+// The reference files of /newer: and /older:
+var newerThanFile, olderThanFile string
+
+// longOptionArg is the argument being parsed without its leading '/' or
+// '-'. The long options are only matched against the whole argument, not
+// against what is left of a cluster of single letter options, so that
+// /btsv is still /b /t /s /v.
+var longOptionArg string
+
+// parseLongOption handles the options that are whole words such as /json.
+// They must be checked before the single letter options, otherwise /tsv
+// would be taken as /t followed by sv.
+func parseLongOption(arg string) bool {
+	if strings.HasPrefix(arg, "cols:") {
+		columns, err := dirlist.ParseColumns(arg[len("cols:"):])
+		if err != nil {
+			log.Fatal(err)
 		}
+		options.Columns = columns
+		return true
 	}
 
-	const spaces = "                                                          "
-	const maxSpacesLen = len(spaces)
-
-	maxLen++ // Need at least one space between names
-	if maxLen > maxSpacesLen {
-		maxLen = maxSpacesLen
-	}
-	entriesPerLine := wideFormatLineWidth / maxLen
-	if entriesPerLine == 0 {
-		entriesPerLine = 1
-	}
-	i, line := 0, ""
-    for _, x := range infos {
-		name := x.Name()
-		if x.IsDir() {
-			name = "[" + name + "]"
-		}
-		line = line + name
-		i++
-		if i == entriesPerLine {
-			listing = append(listing, line)
-			i, line = 0, ""
-		} else {
-			spacing := maxLen - len(name)
-			if spacing < 0 {
-				spacing = 0
-			} else if spacing > maxSpacesLen {
-				spacing = maxSpacesLen
+	for _, x := range []string{"x:", "xf:", "xd:"} {
+		if strings.HasPrefix(arg, x) && len(arg) > len(x) {
+			pattern := arg[len(x):]
+			if x == "xd:" {
+				options.ExcludeDirectoryPatterns = append(
+					options.ExcludeDirectoryPatterns, pattern)
+			} else {
+				options.ExcludeFilePatterns = append(
+					options.ExcludeFilePatterns, pattern)
 			}
-			line += spaces[:spacing]
+			return true
 		}
 	}
-	if i > 0 {
-		listing = append(listing, line)
-	}
-	return listing
-}
-
 
-func getWindowsLongFileListing(infos []util.PathInfo, sizeWidth int) []string {
-	listing := make([]string, len(infos), len(infos))
-	listingFormat := "%04d-%02d-%02d  %02d:%02d %s  %" +
-		strconv.Itoa(sizeWidth) +"s %s"
-	for i, info := range(infos) {
-		isSymlink := info.Mode() & os.ModeSymlink == os.ModeSymlink
-		name := info.Name()
-		pathName := info.PathName()
-		isDir := info.IsDir()
-		var linkTarget string
-		if isSymlink {
-			// fmt.Println(pathName)
-			// if link, err := filepath.EvalSymlinks(pathName); err != nil {
-			if link, err := util.Readlink(pathName); err == nil {
-				linkTarget = " [" + link + "]"
-				if !isDir {
-					// hack hack hack
-					// treat links to directories as directory so <JUNCTION>
-					// will appear in the listing just as under Windows
-					var targetInfo os.FileInfo
-					if targetInfo, err = os.Stat(link); err == nil {
-						isDir = targetInfo.IsDir()
-					}
-				}
+	for _, x := range []string{"depth:", "mindepth:"} {
+		if strings.HasPrefix(arg, x) {
+			value, err := strconv.Atoi(arg[len(x):])
+			if err != nil || value < 0 {
+				log.Fatalf("Bad depth \"%s\"", arg)
+			}
+			if x == "depth:" {
+				options.MaxDepth = value
+			} else {
+				options.MinDepth = value
 			}
+			options.IsRecurseSubDirectory = true
+			return true
 		}
+	}
 
-		if isBareDisplayFormat {
-			displayName := pathName
-			if !isShowFullPath &&
-				strings.HasPrefix(pathName, currentWorkingDirectory) {
-				displayName = displayName[displayPathStart:]
-			}
-			if info.IsDir() && !isShowDirectoryOnly {
-				displayName = "[" + displayName + "]"
-			}
-			if linkTarget != "" {
-				displayName += linkTarget
-			}
-			if isShowQuoteForFileWithSpaces &&
-				strings.Contains(displayName, " ") {
-				displayName = "\"" + displayName + "\""
-			}
-			listing[i] = displayName
-			continue
+	if strings.HasPrefix(arg, "tree:") {
+		value, err := strconv.Atoi(arg[len("tree:"):])
+		if err != nil || value < 0 {
+			log.Fatalf("Bad depth \"%s\"", arg)
 		}
+		options.IsDiskUsageTree = true
+		options.TreeDepth = value
+		return true
+	}
 
-		var size string
-		if isDir {
-			totalDirectoriesCount++
-			if isSymlink {
-				size = "<JUNCTION>    "
-			} else {
-				size = "<DIR>         "
+	if strings.HasPrefix(arg, "tree-min:") {
+		value := arg[len("tree-min:"):]
+		if strings.HasSuffix(value, "%") {
+			percent, err := strconv.ParseFloat(value[:len(value)-1], 64)
+			if err != nil || percent < 0 || percent > 100 {
+				log.Fatalf("Bad percentage \"%s\"", arg)
 			}
+			options.TreeMinPercent = percent
 		} else {
-			size = format.CommaSeparated(info.Size())
+			size, err := dirlist.ParseSize(value)
+			if err != nil {
+				log.Fatal(err)
+			}
+			options.TreeMinSize = size
 		}
+		return true
+	}
 
-		t := info.ModTime().Local()
-		hour, amPM := t.Hour(), "AM"
-		if hour > 12 {
-			hour, amPM = hour - 12, "PM"
-		}
-		displayName := pathName
-		if isShowPartialPath {
-			if strings.HasPrefix(pathName, currentWorkingDirectory) {
-				displayName = pathName[displayPathStart:]
-			} 
-		} else if !isShowFullPath {
-			displayName = name
+	for _, x := range []string{"size:", "asize:"} {
+		if strings.HasPrefix(arg, x) {
+			r, err := dirlist.ParseSizeRange(arg[len(x):])
+			if err != nil {
+				log.Fatal(err)
+			}
+			r.IsAllocated = x == "asize:"
+			options.SizeRanges = append(options.SizeRanges, r)
+			return true
 		}
-		listing[i] = fmt.Sprintf(listingFormat,
-			t.Year(), t.Month(), t.Day(), hour, t.Minute(), amPM,
-			size, displayName + linkTarget)
 	}
-	return listing
-}
-
 
-func printDirectoryListing(listing []string) {
-	const omitted = "..........  ..... .."
-	if numberOfHeadLines != 0 && numberOfHeadLines < len(listing) {
-		listing = append(listing[:numberOfHeadLines], omitted)
-	} else if numberOfTailLines != 0 && numberOfTailLines < len(listing) {
-		listing = listing[len(listing) - numberOfTailLines - 1:]
-		listing[0] = omitted
+	if strings.HasPrefix(arg, "space:") {
+		spaceMode, err := dirlist.ParseSpaceMode(arg[len("space:"):])
+		if err != nil {
+			log.Fatal(err)
+		}
+		options.SpaceMode = spaceMode
+		return true
 	}
 
-	for _, line := range listing {
-		fmt.Println(line)
+	for _, x := range []string{"since:", "until:"} {
+		if strings.HasPrefix(arg, x) {
+			t, isDateOnly, err := dirlist.ParseTimeSpec(arg[len(x):],
+				time.Now())
+			if err != nil {
+				log.Fatal(err)
+			}
+			if x == "since:" {
+				options.FileCutoffTime = t
+			} else if isDateOnly {
+				// Until the end of that day
+				options.FileUntilTime = t.AddDate(0, 0, 1)
+			} else {
+				options.FileUntilTime = t
+			}
+			return true
+		}
 	}
-}
 
-
-func showDirectoryListing(directory string, args []string) error {
-	// fmt.Println("directory:", directory)
-	f, err := os.Open(directory)
-	if err != nil {
-		return err
+	if strings.HasPrefix(arg, "newer:") {
+		newerThanFile = arg[len("newer:"):]
+		return true
 	}
-
-	// ioutil.ReadDir() is not used because we don't need the names to be sorted
-	allInfos, err := f.Readdir(-1); f.Close()
-	if err != nil {
-		return err
+	if strings.HasPrefix(arg, "older:") {
+		olderThanFile = arg[len("older:"):]
+		return true
 	}
 
-	subDirectories := []string{}
-	infos := make([]util.PathInfo, 0, len(allInfos))
-
-	var totalSizes, maxSize int64
-	filesCount, directoriesCount, maxNameLen := 0, 0, 0
-	for _, x := range allInfos {
-		name := x.Name()
-		if name == "." || name == ".." {
-			continue
-		}
-		pathName := filepath.Join(directory, name)
-		isDir := x.IsDir() 
-		if isDir {
-			if isRecurseSubDirectory {
-				subDirectories = append(subDirectories, pathName)
-			}
-			if isExcludeDirectory {
-				continue
-			}
-		} else if isShowDirectoryOnly {
-			continue
+	if strings.HasPrefix(arg, "t:") {
+		// Like dir /t:c, c is the creation (birth) time, the same letters
+		// as /df:
+		field, err := dirlist.ParseTimeField(arg[len("t:"):])
+		if err != nil {
+			log.Fatal(err)
 		}
+		options.TimeField = field
+		return true
+	}
 
-		if x.ModTime().Before(fileCutoffTime) {
-			continue
-			// && !(isDir && isMatchAllFiles) {
-			// cutoff does not exclude directories unless match is specified
+	if strings.HasPrefix(arg, "tf:") {
+		format, err := dirlist.ParseTimeFormat(arg[len("tf:"):])
+		if err != nil {
+			log.Fatal(err)
 		}
+		options.TimeFormat = format
+		return true
+	}
 
-		matched := isMatchAllFiles
-		if !matched {
-		    target := name
-		    if isIgnoreFilenameCase {
-				target = strings.ToLower(name)
-			}
-			for _, y := range(args) {
-				if matched, err = filepath.Match(y, target); err != nil {
-					return err
-				} 
-				if matched {
-					break
-				}
-			}
+	if strings.HasPrefix(arg, "sf:") {
+		sizeFormat, err := dirlist.ParseSizeFormat(arg[len("sf:"):])
+		if err != nil {
+			log.Fatal(err)
 		}
+		options.SizeFormat = sizeFormat
+		return true
+	}
 
-		if matched {
-			if isExcludeHiddenFiles || isShowHiddenFilesOnly {
-				if hidden, err1 := util.IsHiddenFile(pathName, true); err1!=nil{
-					// the error "The filename, directory name,
-					// or volume label syntax is incorrect." will occur
-					// if the directory is somehow corrupted, but want to
-					// continue anyway
-					fmt.Printf("Warning \"%s\": %v", pathName, err1)
-					continue
-				} else if hidden {
-					// fmt.Println("hidden:", name)
-					if isExcludeHiddenFiles && !(isDir && isShowDirectoryOnly) {
-						// Follow "dir /ad" to show hidden directories
-						continue
-					}
-				} else {
-					// hack hack hack: also show system files
-					if system, err2 := util.IsSystemFile(pathName); err2 != nil{
-						return err2
-					} else if system && !(isDir && isShowDirectoryOnly) {
-						fmt.Println("system:", name)
-						// Follow "dir /ah " to show system directories
-						if isExcludeHiddenFiles {
-							continue
-						}
-					}
-					if isShowHiddenFilesOnly {
-						continue
-					}
-				}
-			}
-			if isExcludeReadOnlyFiles || isShowReadOnlyFilesOnly {
-				if readonly, err1 := util.IsReadOnlyFile(pathName); err1 != nil{
-					// the error "The filename, directory name,
-					// or volume label syntax is incorrect." will occur
-					// if the directory is somehow corrupted, but want to
-					// continue anyway
-					fmt.Printf("Warning \"%s\": %v", pathName, err1)
-					continue
-				} else if readonly {
-					// fmt.Println("hidden:", name)
-					if isExcludeReadOnlyFiles {
-						continue
-					}
-				} else if isShowReadOnlyFilesOnly {
-					continue
-				}
-			}
-
-			infos = append(infos, util.NewPathInfo(x, pathName))
-			if isDir {
-				directoriesCount++
-			} else {
-				filesCount++
-				size := x.Size()
-				totalSizes += size
-				if size > maxSize {
-					maxSize = size
-				}
-			}
-			if len(name) > maxNameLen {
-				maxNameLen = len(name) 
-			}
-		}
-	}		
-
-	if isSortByTime {
-		sort.Sort(byTime(infos))
-	} else if isSortBySize {
-		sort.Sort(bySize(infos))
-	} else if isSortByExtension {
-		sort.Sort(byExtension(infos))
-	} else if isSortByTimeReversed {
-		sort.Sort(byTimeReversed(infos))
-	} else if isSortBySizeReversed {
-		sort.Sort(bySizeReversed(infos))
-	} else if isSortByExtensionReversed {
-		sort.Sort(byExtensionReversed(infos))
-	} else if isSortByName {
-		sort.Sort(byName(infos))
-	} else if isSortByNameReversed {
-		sort.Sort(byNameReversed(infos))
-	} else if isSortByDirThenName {
-		sort.Sort(byDirectoryThenName(infos))
-	}
-
-	var listing []string
-	if isWideDisplayFormat {
-		listing = getWideFormatFileListing(infos)
-	} else if isUnixStyleListing {
-		listing = cmd.GetUnixLongFileListing(infos, isShowFullPath,
-			isShowPartialPath, isShowNumericUnixFileMode,
-			currentWorkingDirectory, displayPathStart)
-	} else {
-		sizeFieldWidth := cmd.MaxFileSizeWidth
-		if maxNameLen > wideFormatLineWidth - (cmd.MaxFileSizeWidth+20) {
-			// Try to use cmd.MaxFileSizeWidth, unless maxNameLen is larger
-			// than the available width 
-			sizeFieldWidth = len(format.CommaSeparated(maxSize))
+	if strings.HasPrefix(arg, "unit:") {
+		unit, err := dirlist.ParseSizeUnit(arg[len("unit:"):])
+		if err != nil {
+			log.Fatal(err)
 		}
-		listing = getWindowsLongFileListing(infos, sizeFieldWidth)
+		options.SizeUnit = unit
+		return true
 	}
 
-	printDirectoryListing(listing)
-
-	if (filesCount > 0 || directoriesCount > 0) /*&& !isBareDisplayFormat*/ {
-		relativeDirectory := directory
-		if strings.HasPrefix(directory,filepath.Dir(currentWorkingDirectory)) &&
-			len(directory) > displayDirStart {
-			relativeDirectory = directory[displayDirStart:]
-			if strings.IndexByte(relativeDirectory, os.PathSeparator) < 0 {
-				relativeDirectory = fmt.Sprintf(".%c%s", os.PathSeparator,
-					relativeDirectory)
-			}
-		}
-		fmt.Println()
-		if filesCount == 1 && !isBareDisplayFormat {
-			fmt.Printf("%" + strconv.Itoa(cmd.MaxFileSizeWidth + 5) +
-				"s Only one file in %s\n", "", relativeDirectory)
-		} else if filesCount > 1 {
-			fmt.Printf("%" + strconv.Itoa(cmd.MaxFileSizeWidth + 22) + 
-				"s %s\n", fmt.Sprintf("%d Files %s (%d bytes)", filesCount, 
-					format.CommaSeparated(totalSizes), totalSizes),
-				relativeDirectory)
-		} else if directoriesCount == 1 {
-			fmt.Printf("%" + cmd.MaxFileSizeWidthText +
-				"s Only one directory in %s\n", "", relativeDirectory)
-		} else if directoriesCount > 1 {
-			fmt.Printf("%" + cmd.MaxFileSizeWidthText +
-				"d directories in %s\n",
-				directoriesCount, relativeDirectory)
-		}
-		if isRecurseSubDirectory {
-			fmt.Println()
-		}
-	}
-
-	for _, x := range subDirectories {
-		if err = showDirectoryListing(x, args); err != nil {
-			// return err
-			fmt.Println(err)
-			continue
+	if strings.HasPrefix(arg, "tz:") {
+		location, err := time.LoadLocation(arg[len("tz:"):])
+		if err != nil {
+			log.Fatalf("Bad time zone \"%s\": %v", arg, err)
 		}
+		options.Location = location
+		return true
 	}
 
-	totalDirectoriesCount += directoriesCount
-	totalFilesCount += filesCount
-	totalFilesSize += totalSizes
-	return nil
-}
-
-
-func showAbsPathList(pathList []string) error {
-	// because the paths could be anywhere in the system, must show at least
-	// part of the path to distiguish between dir1/abc and dir2/abc
-	// fmt.Printf("%t showAbsPathList: %v\n", isShowFullPath, pathList)
-	isShowPartialPath = !isShowFullPath
-
-	totalFilesCount, totalFilesSize = 0, 0
-	infos := make([]util.PathInfo, 0, len(pathList))
-	for _, pathName := range pathList {
-		info, err := os.Lstat(pathName)
+	if strings.HasPrefix(arg, "df:") {
+		field, err := dirlist.ParseTimeField(arg[len("df:"):])
 		if err != nil {
-			// fmt.Printf("%s: %s\n", pathName, err)
-			fmt.Printf("%s\n", err)
-		} else {
-			infos = append(infos, util.NewPathInfo(info, pathName))
-			totalFilesCount++
-			totalFilesSize += info.Size()
+			log.Fatal(err)
 		}
+		options.DateFilterField = field
+		return true
 	}
 
-	var listing []string
-	if isUnixStyleListing {
-		listing = cmd.GetUnixLongFileListing(infos, isShowFullPath,
-			isShowPartialPath, isShowNumericUnixFileMode,
-			currentWorkingDirectory, displayPathStart)
-	} else {
-		listing = getWindowsLongFileListing(infos, cmd.MaxFileSizeWidth)
+	if strings.HasPrefix(arg, "j:") {
+		value, err := strconv.Atoi(arg[len("j:"):])
+		if err != nil || value < 1 {
+			log.Fatalf("Bad number of jobs \"%s\"", arg)
+		}
+		options.Jobs = value
+		return true
 	}
-	printDirectoryListing(listing)
-	fmt.Println()
 
-	isMatchAllFiles = true 
-	isExcludeHiddenFiles = true
-	isShowPartialPath = false
-	args := []string{}
-	for _, x := range infos {
-		// fmt.Println("x.IsDir()", x.IsDir())
-		if x.IsDir() {
-			showDirectoryListing(x.PathName(), args)
-			fmt.Println()
-		}
+	if strings.HasPrefix(arg, "locale:") {
+		options.Locale = arg[len("locale:"):]
+		return true
 	}
 
-	return nil
+	switch arg {
+	case "json": options.IsJSONFormat = true
+	case "ndjson": options.IsNDJSONFormat = true
+	case "csv": options.IsCSVFormat = true
+	case "tsv": options.IsTSVFormat = true
+	case "posix": options.IsPosixGlob = true
+	case "gitignore": options.IsHonourIgnoreFiles = true
+	case "one-file-system": options.IsOneFileSystem = true
+	case "flat": options.IsFlatListing = true
+	case "utc": options.Location = time.UTC
+	case "links": options.IsShowLinkCount = true
+	case "multilink": options.IsMultiLinkOnly = true
+	case "ds": // no longer /d /s, which must now be written as two options
+		options.IsShowDirectorySize = true
+	case "tree": options.IsDiskUsageTree = true
+	case "ignored":
+		options.IsHonourIgnoreFiles = true
+		options.IsShowIgnoredOnly = true
+	default:
+		return false
+	}
+	return true
 }
 
-
-var isOptionMustStartWithMinus bool
-
 func parseOneOption(arg string) (bool, string) {
+	if strings.TrimLeft(arg, "-") == longOptionArg && parseLongOption(arg) {
+		return true, ""
+	}
+
 	ch := arg[0]
 	if ch == 'd' || ch == 'h' || ch == 't' || ch == 'w' {
 		value, restOfArg := cmd.ParseNumericArg(arg, 0)
@@ -539,23 +272,23 @@ func parseOneOption(arg string) (bool, string) {
 			if value == 0 {
 				value = 1
 			}
-			fileCutoffTime = time.Now().
+			options.FileCutoffTime = time.Now().
 				Add(-time.Duration(value * 24) * time.Hour)
 		case 'h':
-			numberOfHeadLines = value
-			if numberOfHeadLines == 0 {
-				numberOfHeadLines = 25
+			options.NumberOfHeadLines = value
+			if options.NumberOfHeadLines == 0 {
+				options.NumberOfHeadLines = 25
 			} 
 		case 't':
-			numberOfTailLines = value
-			if numberOfTailLines == 0 {
-				numberOfTailLines = 25
+			options.NumberOfTailLines = value
+			if options.NumberOfTailLines == 0 {
+				options.NumberOfTailLines = 25
 			}
 		case 'w':
 			if value > 0 {
-				wideFormatLineWidth = value
+				options.WideFormatLineWidth = value
 			}
-			isWideDisplayFormat = true
+			options.IsWideDisplayFormat = true
 			
 		default:
 			panic("Unknown ch(" + arg[:1] + ")")
@@ -567,24 +300,25 @@ func parseOneOption(arg string) (bool, string) {
 	returnIndex := 1
 	switch ch {
 	case '?': usage(); os.Exit(1)
-	case 'q': isShowQuoteForFileWithSpaces = true
-		isBareDisplayFormat = true
-	case 'b': isBareDisplayFormat = true
-	case 'f': isShowFullPath = true
+	case 'q': options.IsShowQuoteForFileWithSpaces = true
+		options.IsBareDisplayFormat = true
+	case 'b': options.IsBareDisplayFormat = true
+	case 'f': options.IsShowFullPath = true
 	case 'v': isShowVolumeInformation = true
+	case 'l': options.IsFollowLinks = true
 	case 'z', 's', 'r':
-		isRecurseSubDirectory = true
+		options.IsRecurseSubDirectory = true
 		if ch == 'z' {
-			isShowFullPath = true
+			options.IsShowFullPath = true
 		}
-		if isBareDisplayFormat {
-			isExcludeDirectory = !isShowDirectoryOnly
+		if options.IsBareDisplayFormat {
+			options.IsExcludeDirectory = !options.IsShowDirectoryOnly
 		}
 
 	case '-':
 		returnIndex++
 		if strings.HasPrefix(arg, "-c") {
-			isNoCommaSeparator = true
+			options.IsNoCommaSeparator = true
 		} else {
 			return false, arg
 		}
@@ -592,79 +326,81 @@ func parseOneOption(arg string) (bool, string) {
 	case 'a':
 		returnIndex++
 		if strings.HasPrefix(arg, "ad") {
-			if isExcludeDirectory {
+			if options.IsExcludeDirectory {
 				log.Fatal("Can not use both /ad and /a-d")
 			}
-			isShowDirectoryOnly = true
+			options.IsShowDirectoryOnly = true
 		} else if strings.HasPrefix(arg, "ah") || strings.HasPrefix(arg, "as") {
 			isHiddenOptionExplicit = true
-			isShowHiddenFilesOnly = true
-			isExcludeHiddenFiles = false
+			options.IsShowHiddenFilesOnly = true
+			options.IsExcludeHiddenFiles = false
 		} else if strings.HasPrefix(arg, "ao") {
-			isShowReadOnlyFilesOnly = true
-			isExcludeReadOnlyFiles = false
+			options.IsShowReadOnlyFilesOnly = true
+			options.IsExcludeReadOnlyFiles = false
 		} else {
 			returnIndex++
 			if strings.HasPrefix(arg, "a-d") {
-				if isShowDirectoryOnly {
+				if options.IsShowDirectoryOnly {
 					log.Fatal("Can not use both /a-d and /ad")
 				}
-				isExcludeDirectory = true
+				options.IsExcludeDirectory = true
 			} else if strings.HasPrefix(arg, "a-h") ||
 				strings.HasPrefix(arg, "a-s") {
 				isHiddenOptionExplicit = true
-				if isShowHiddenFilesOnly {
+				if options.IsShowHiddenFilesOnly {
 					log.Fatal("Can not use both /a-h and /ah")
 				}
-				isExcludeHiddenFiles = true
+				options.IsExcludeHiddenFiles = true
 			} else if strings.HasPrefix(arg, "a-o") {
-				if isShowReadOnlyFilesOnly {
+				if options.IsShowReadOnlyFilesOnly {
 					log.Fatal("Can not use both /a-o and /ao")
 				}
-				isExcludeReadOnlyFiles = true
+				options.IsExcludeReadOnlyFiles = true
 			} else {
 				return false, arg
 			}
 		}
 
 	case 'o':
+		if arg == "o:none" {
+			options.IsUnsorted = true
+			return true, ""
+		}
+		if strings.HasPrefix(arg, "o:") {
+			// The rest of the argument is a sort specification like /o:gn-d
+			keys, err := dirlist.ParseSortSpec(arg[2:])
+			if err != nil {
+				log.Fatal(err)
+			}
+			options.SortKeys = keys
+			return true, ""
+		}
+		// Without ':' only one key is taken so that more options can follow,
+		// e.g. /osbs
 		returnIndex++
-		if strings.HasPrefix(arg, "on") {
-			isSortByName = true; isSortByDirThenName = false
-		} else if strings.HasPrefix(arg, "og") {
-			isSortByName = true; isSortByDirThenName = true
-		} else if strings.HasPrefix(arg, "os") {
-			isSortBySize = true
-		} else if strings.HasPrefix(arg, "od") {
-			isSortByTime = true
-		} else if strings.HasPrefix(arg, "oe") {
-			isSortByExtension = true
-		} else {
+		if strings.HasPrefix(arg, "o-") {
 			returnIndex++
-			if strings.HasPrefix(arg, "o-n") {
-				isSortByNameReversed = true
-			} else if strings.HasPrefix(arg, "o-s") {
-				isSortBySizeReversed = true
-			} else if strings.HasPrefix(arg, "o-d") {
-				isSortByTimeReversed = true
-			} else if strings.HasPrefix(arg, "o-e") {
-				isSortByExtensionReversed = true
-			} else {
-				return false, arg
-			}
 		}
+		if returnIndex > len(arg) {
+			return false, arg
+		}
+		keys, err := dirlist.ParseSortSpec(arg[1:returnIndex])
+		if err != nil {
+			return false, arg
+		}
+		options.SortKeys = keys
 
 	case 'u':
 		if isUnix {
-			isUnixStyleListing = true
+			options.IsUnixStyleListing = true
 			break
 		}
 		return false, arg
 
 	case 'x':
 		if isUnix {
-			isUnixStyleListing = true
-			isShowNumericUnixFileMode = true
+			options.IsUnixStyleListing = true
+			options.IsShowNumericUnixFileMode = true
 			break
 		}
 		return false, arg
@@ -684,20 +420,81 @@ func usage() {
         "    /w(wide) /b(are) /f(ullpath) \n" +
         "    /s(ubdirectory) or /r   search in subdirectories\n" +
         "    /z                      Like /s but show full path\n" +
+        "    /l                      With /s follow links to directories\n" +
+        "    /one-file-system        With /s do not descend into other mounts\n" +
+        "    /flat                   With /s sort the whole tree as one list\n" +
+        "    /j:N                    With /s read N directories at once (8)\n" +
+        "    /depth:N /mindepth:N    Like /s but only list depth N or less\n" +
+        "                            (N or more), 1 is the current directory\n" +
         "    /h(head)[0-9]+          Show first few lines of listing\n" +
         "    /t(ail)[0-9]+           Show last few lines of listing\n" +
         "    /d(ays)[0-9]+           Show files no older than x days\n" +
+        "    /since:D /until:D       Show files changed since/until a date\n" +
+        "                            like 2024-01-31[T13:45] or 90m 6h 3d 2w ago\n" +
+        "    /newer:f /older:f       Show files newer/older than file f\n" +
+        "    /t:c /t:a /t:w          Show and sort on the creation, last access\n" +
+        "                            or last write (default) time, /t:b is the\n" +
+        "                            same as /t:c and /t:s is the status change\n" +
+        "    /tf:24h /tf:iso         Time format: 24h, iso, iso-ns, relative or\n" +
+        "                            a Go layout like \"02/01/2006 15:04:05\"\n" +
+        "    /-c                     Show the sizes without comma (/sf:raw)\n" +
+        "    /sf:raw|grouped|iec|si  Size format: bytes, bytes with the digit\n" +
+        "                            groups of the locale, 1.5 KiB or 1.5 kB\n" +
+        "    /unit:M /unit:GiB       Show the sizes in that unit\n" +
+        "    /utc /tz:Asia/Tokyo     Show the times in UTC or in a time zone\n" +
+        "    /df:w|a|c|s             Date filters use the write, access,\n" +
+        "                            creation or status change time, the same\n" +
+        "                            letters as /t: (default is the /t: time)\n" +
+        "    /size:>10M /size:<1KiB  Show files by size, also >= <= = N-M N- -M\n" +
+        "                            and empty (K M G T = 1000^n, KiB MiB GiB\n" +
+        "                            TiB)\n" +
+        "    /asize:>10M             Same on the allocated (on disk) size\n" +
+        "    /space:apparent|alloc|both  Show and total the apparent size,\n" +
+        "                            the allocated size or both\n" +
+        "    /links                  Show the number of hard links (a file\n" +
+        "                            with several links is counted once in\n" +
+        "                            the sizes, /ds and /tree)\n" +
+        "    /ds                     Show the total size of the directories\n" +
+        "                            (also sorted on by /os) instead of <DIR>,\n" +
+        "                            /d /s shows the files of the last day\n" +
+        "    /multilink              Only show files with several hard links\n" +
+        "    /tree /tree:N           Show the size of the directories as a tree\n" +
+        "                            (N levels deep) sorted by size, like du\n" +
+        "    /tree-min:1M /tree-min:5%%  With /tree group the directories smaller\n" +
+        "                            than a size or a percentage of the parent\n" +
         "    /on /od /os /oe /og     Sort by name, date, size, ext, dir\n" +
+        "    /oa                     Sort by allocated size\n" +
+        "    /o:gn-d                 Sort by several keys (- to reverse)\n" +
+        "    /oN /oE                 Natural name/ext order (file2 < file10)\n" +
+        "    /o:none                 Unsorted, print entries as they are read\n" +
         "    /ad /a-d                Only show directory (- to exclude)\n" +
         "    /ah /a-h                Only show hidden/system (- to exclude)\n" +
         "    /as /a-s                Same as /ah /a-h\n" +
         "    /ao /a-o                Only show read-only (- to exclude)\n" +
 		"    /q						 Quote filename with space (implies /b)\n" +
-        "    /v                      Show volume info\n", xdir)
+        "    /v                      Show volume info\n" +
+        "    src/**/testdata/*.json  Path pattern, ** matches any directories\n" +
+        "    /xf:*.tmp or !*.tmp     Exclude matching files (repeatable)\n" +
+        "    /xd:node_modules        Exclude and do not descend into directory\n" +
+        "    /gitignore              Skip files in .gitignore, .ignore and\n" +
+        "                            .git/info/exclude (and .git itself)\n" +
+        "    /ignored                Only show the files that are ignored\n" +
+        "    /locale:de_DE           Sort names for a locale (default from\n" +
+        "                            LC_ALL, LC_COLLATE or LANG, C for bytes)\n" +
+        "    /json /ndjson           JSON output (NDJSON: one object per line)\n" +
+        "    /csv /tsv               CSV or tab separated output with header\n" +
+        "    /cols:name,size,...     Columns for /csv and /tsv, any of\n" +
+        "                            " + strings.Join(dirlist.CSVColumns, ",") +
+		"\n",
+		xdir)
 	if isUnix {
 		fmt.Printf("     /u(nix style)           Unix style listing\n")
 		fmt.Printf("     /x(xx Unix file mode)   Unix numeric mode listing \n")
 	}
+	fmt.Printf("\n" +
+		"Wildcards work as in the Windows dir command: *.* also matches\n" +
+		"names without '.', *. only matches names without extension and\n" +
+		"[ is not special. Use /posix for the Unix glob behavior instead.\n")
 	fmt.Printf("\n" +
 		"Unlike the original Windows dir command, hidden files \n" +
 		"are shown by default if a glob pattern is specified.\n" +
@@ -706,30 +503,90 @@ func usage() {
 	fmt.Printf("Options can start with '-' instead of '/', " +
 		"but don't mix them. For example:\n" + 
 		"     %s /h10t15osbs d:\\workspace\\go\\src*.go *.txt\n" +
-		"     %s -h10t15osbs ~/workspace/go/src*.go *.txt\n\n", xdir, xdir)
+		"     %s -h10t15osbs ~/workspace/go/src*.go *.txt\n" +
+		"Word options such as /json, /tsv or /ds must be on their own,\n" +
+		"/btsv is /b /t /s /v and /ads is /ad /s.\n\n", xdir, xdir)
 	cmd.PrintUsageOptionEnvironmentVariables(xdir, optionEnvironmentVariable,
 		caseSensitivityEnvironmentVariable)
 }
 
-var currentWorkingDirectory string
-var displayPathStart, displayDirStart int
 var startDirectory string
 
+// extractExcludePatterns moves the negated patterns such as !*.tmp from args
+// to options.ExcludeFilePatterns and folds the case of all the exclude
+// patterns if needed
+func extractExcludePatterns(args []string) []string {
+	nameArgs := []string{}
+	for _, x := range args {
+		if len(x) > 1 && x[0] == '!' {
+			options.ExcludeFilePatterns = append(options.ExcludeFilePatterns,
+				x[1:])
+		} else {
+			nameArgs = append(nameArgs, x)
+		}
+	}
+	if options.IsIgnoreFilenameCase {
+		for _, patterns := range [][]string{options.ExcludeFilePatterns,
+			options.ExcludeDirectoryPatterns} {
+			for i, x := range patterns {
+				patterns[i] = dirlist.FoldCase(x, options.Locale)
+			}
+		}
+	}
+	return nameArgs
+}
+
+// extractPathPatterns moves the path patterns such as src/**/testdata/*.json
+// from args to options.PathPatterns. Only the directory before their first
+// wildcard is returned to be used as the start directory.
+func extractPathPatterns(args []string) []string {
+	nameArgs := []string{}
+	pathPatternDirectory := ""
+	for _, x := range args {
+		if !dirlist.IsPathPattern(x) {
+			nameArgs = append(nameArgs, x)
+			continue
+		}
+		directory, pattern := dirlist.SplitPathPattern(x)
+		if len(options.PathPatterns) > 0 && directory != pathPatternDirectory {
+			log.Fatalf("Path patterns must all start from the same " +
+				"directory: %s", x)
+		}
+		pathPatternDirectory = directory
+		if options.IsIgnoreFilenameCase {
+			for i, y := range pattern {
+				pattern[i] = dirlist.FoldCase(y, options.Locale)
+			}
+		}
+		options.PathPatterns = append(options.PathPatterns, pattern)
+	}
+	if len(options.PathPatterns) == 0 {
+		return args
+	}
+	if len(nameArgs) > 0 {
+		log.Fatalf("You can not specify pattern %v in combination with " +
+			"path patterns", nameArgs)
+	}
+	return []string{filepath.Join(pathPatternDirectory, "*")}
+}
+
 func parseArgAsOptions(arg string) bool {
+	longOptionArg = strings.TrimLeft(arg, "/-")
 	return cmd.ParseCommandLineOptions(arg, &isOptionMustStartWithMinus,
 		parseOneOption)
 }
 
 func main() {
 	log.SetFlags(0)
-	// wideFormatLineWidth = cmd.InitializeConsoleScreenWidth()
-	wideFormatLineWidth = cmd.GetConsoleScreenWidth()
-	isIgnoreFilenameCase =
+	// options.WideFormatLineWidth = cmd.InitializeConsoleScreenWidth()
+	options.WideFormatLineWidth = cmd.GetConsoleScreenWidth()
+	options.IsIgnoreFilenameCase =
 		!cmd.IsFileNameCaseSensitive(caseSensitivityEnvironmentVariable)
-	wideFormatLineWidth = 80
+	options.Locale = dirlist.LocaleFromEnvironment()
+	options.WideFormatLineWidth = 80
 
-	if options := os.Getenv(optionEnvironmentVariable); len(options) > 0 {
-		for _, x := range strings.Split(options, " ") {
+	if defaults := os.Getenv(optionEnvironmentVariable); len(defaults) > 0 {
+		for _, x := range strings.Split(defaults, " ") {
 			if !parseArgAsOptions(x) {
 				log.Fatal("Bad default option: ", x)
 			}
@@ -742,17 +599,48 @@ func main() {
 		if !parseArgAsOptions(x) {
 			// Don't do it here since it will mess up the src directory
 			// which is case sentive under Linux
-			// if isIgnoreFilenameCase {
+			// if options.IsIgnoreFilenameCase {
 			// 	    x = string.ToLower(x)
 			// }
 			args = append(args, x)
 		}
 	}
 
-	currentWorkingDirectory, startDirectory,
-	displayPathStart, displayDirStart, isMatchAllFiles, args =
-		cmd.ExtractStartDirectory(args)
-	// fmt.Println("startDirectory:", startDirectory, displayPathStart)
+	// Done once all the options are known since /df: and /t: can come
+	// after them
+	dateFilterField := options.DateFilterField
+	if dateFilterField == 0 {
+		dateFilterField = options.TimeField
+	}
+	if newerThanFile != "" {
+		t, err := dirlist.FileTime(newerThanFile, dateFilterField)
+		if err != nil {
+			log.Fatal(err)
+		}
+		options.FileCutoffTime = t.Add(time.Nanosecond)
+	}
+	if olderThanFile != "" {
+		t, err := dirlist.FileTime(olderThanFile, dateFilterField)
+		if err != nil {
+			log.Fatal(err)
+		}
+		options.FileUntilTime = t
+	}
+
+	if options.IsDiskUsageTree && options.IsMachineReadable() {
+		log.Fatal("Can not use /tree with /json, /ndjson, /csv or /tsv")
+	}
+
+	args = extractExcludePatterns(args)
+	args = extractPathPatterns(args)
+	options.CurrentWorkingDirectory, startDirectory,
+	options.DisplayPathStart, options.DisplayDirStart,
+	options.IsMatchAllFiles, args = cmd.ExtractStartDirectory(args)
+	if len(options.PathPatterns) > 0 {
+		// The "*" added by extractPathPatterns() is not a real pattern
+		options.IsMatchAllFiles, args = false, nil
+	}
+	// fmt.Println("startDirectory:", startDirectory)
 
 	diskVolumeName, diskSerialNumber, err := util.
 		GetDiskVolumeNameSerialNumber(startDirectory)
@@ -764,26 +652,31 @@ func main() {
 	// 	diskVolumeName = "?????"
 	// }
 
-	if diskVolumeName != "" && !isBareDisplayFormat  && (isMatchAllFiles ||
-		isShowVolumeInformation) {
+	if diskVolumeName != "" && !options.IsBareDisplayFormat &&
+		!options.IsMachineReadable() &&
+		(options.IsMatchAllFiles || isShowVolumeInformation) {
 		fmt.Printf("Volume in drive %s is %s, Serial %04X-%04X\n",
 			strings.ToUpper(startDirectory[:2]), diskVolumeName,
 			diskSerialNumber >> 16, diskSerialNumber & 0xffff)
 	}
 
-	absArgs := cmd.GetAbsPathListIfNoWildcardFound(startDirectory, args,
-		isRecurseSubDirectory)
-	// fmt.Println("absArgs:", absArgs, startDirectory, isMatchAllFiles)
+	var absArgs []string
+	if len(options.PathPatterns) == 0 {
+		absArgs = cmd.GetAbsPathListIfNoWildcardFound(startDirectory, args,
+			options.IsRecurseSubDirectory)
+	}
+	// fmt.Println("absArgs:", absArgs, startDirectory, options.IsMatchAllFiles)
+	lister := dirlist.NewLister(options, os.Stdout)
 	if len(absArgs) == 0 {
 		if len(args) > 0 && args[0] == "" {
 			args = args[1:]
 		}
-		if isIgnoreFilenameCase {
+		if options.IsIgnoreFilenameCase {
 			for i, arg := range args {
-				args[i] = strings.ToLower(arg)
+				args[i] = dirlist.FoldCase(arg, options.Locale)
 			}
 		}
-		if isMatchAllFiles {
+		if options.IsMatchAllFiles {
 			if len(args) > 1 {
 				log.Fatalf("You can not specify multiple pattern %v " +
 					"in combination with * or *.*", args)
@@ -794,44 +687,75 @@ func main() {
 				// explicityly via /ah or /a-h
 				//
 				// But if the match was to "*.*" or "*" then show them
-				isExcludeHiddenFiles = true
+				lister.IsExcludeHiddenFiles = true
 			}
 		}
-		err = showDirectoryListing(startDirectory, args)
+		if options.IsDiskUsageTree {
+			err = lister.ListTree(startDirectory, args)
+		} else {
+			err = lister.List(startDirectory, args)
+		}
+	} else if options.IsDiskUsageTree {
+		err = lister.ListTrees(absArgs)
 	} else {
-		err = showAbsPathList(absArgs)
+		err = lister.ListPaths(absArgs)
 	} 
 	if err != nil {
 		log.Fatal(err)
 	} 
 
-    if totalFilesCount == 0 && totalDirectoriesCount == 0 {
+	if options.IsMachineReadable() {
+		du, err := util.NewDiskUsage(startDirectory)
+		if err != nil {
+			log.Fatal("NewDiskUsage: ", err)
+		}
+		if err = lister.WriteSummary(du.Free); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	totals := lister.Totals
+	bytesWord := ""
+	if options.IsSizeInBytes() {
+		bytesWord = " bytes"
+	}
+    if totals.FilesCount == 0 && totals.DirectoriesCount == 0 {
 		fmt.Printf("No file found\n")
-	} else if totalFilesCount > 1 &&
-		(isRecurseSubDirectory || len(absArgs) > 0) {
-		fmt.Printf("%5d File(s)  %" + cmd.MaxFileSizeWidthText + "s bytes " +
-			"total\n", totalFilesCount, format.CommaSeparated(totalFilesSize))
+	} else if totals.FilesCount > 1 &&
+		(options.IsRecursive() || options.IsDiskUsageTree ||
+			len(absArgs) > 0) {
+		fmt.Printf("%5d File(s)  %" + cmd.MaxFileSizeWidthText + "s%s " +
+			"total\n", totals.FilesCount,
+			options.FormatSize(totals.FilesSize), bytesWord)
+		if options.SpaceMode == dirlist.SpaceAllocated ||
+			options.SpaceMode == dirlist.SpaceBoth {
+			fmt.Printf("%" + strconv.Itoa(cmd.MaxFileSizeWidth + 16) +
+				"s%s allocated\n",
+				options.FormatSize(totals.AllocatedSize), bytesWord)
+		}
 	}
 
-	if isShowVolumeInformation || !isBareDisplayFormat {
+	if isShowVolumeInformation || !options.IsBareDisplayFormat {
 		du, err := util.NewDiskUsage(startDirectory)
 		if err != nil {
 			log.Fatal("NewDiskUsage: ", err)
 		}
-		freeSpace := format.CommaSeparated(du.Free)
+		// Only the number is padded so that it stays where it always was
+		freeSpace := options.FormatSize(du.Free)
 		if isWindows {
 			if diskVolumeName == "" {
 				diskVolumeName = "<Unknown>"
 			}
 			fmt.Printf("%" + strconv.Itoa(cmd.MaxFileSizeWidth + 16) +
-				"s bytes free in %s (%s, %04X-%04X)\n", freeSpace,
+				"s%s free in %s (%s, %04X-%04X)\n", freeSpace, bytesWord,
 				strings.ToUpper(startDirectory[:2]), diskVolumeName,
 				diskSerialNumber >> 16, diskSerialNumber & 0xffff)
 		} else if len(diskVolumeName) > 0 {
-			fmt.Printf("%32s bytes free in volume %s\n",
-				freeSpace, diskVolumeName)
+			fmt.Printf("%32s%s free in volume %s\n",
+				freeSpace, bytesWord, diskVolumeName)
 		} else {
-			fmt.Printf("%32s bytes free\n", freeSpace)
+			fmt.Printf("%32s%s free\n", freeSpace, bytesWord)
 		}
 	}
 }